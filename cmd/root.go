@@ -1,8 +1,14 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/stenstromen/kubectl-pulse/internal/pulse"
@@ -12,6 +18,19 @@ var (
 	namespace string
 	minutes   int
 	podAmount int
+	output    string
+
+	clientOptions pulse.ClientOptions
+	contexts      []string
+	allContexts   bool
+
+	watch    bool
+	interval time.Duration
+	timeout  time.Duration
+
+	policyPath  string
+	exitCode    bool
+	certHorizon string
 )
 
 var rootCmd = &cobra.Command{
@@ -23,32 +42,267 @@ Example usage:
   kubectl pulse                # Show cluster health with default 15-minute window
   kubectl pulse -n kube-system # Check restarts in the kube-system namespace
   kubectl pulse -m 30          # Check restarts in last 30 minutes
-  kubectl pulse -m 30 -p 10    # Check restarts in last 30 minutes and show top 10 pods`,
+  kubectl pulse -m 30 -p 10    # Check restarts in last 30 minutes and show top 10 pods
+  kubectl pulse -o json        # Print the pulse as JSON for scripts and CI
+  kubectl pulse -o markdown    # Print the pulse as Markdown tables for tickets and PRs
+  kubectl pulse --context prod # Check the cluster behind the "prod" kubeconfig context
+  kubectl pulse --all-contexts # Summarize every context in the kubeconfig
+  kubectl pulse -w             # Refresh every 5 seconds and highlight what changed
+  kubectl pulse --timeout 10s  # Give up after 10 seconds and print what was gathered
+  kubectl pulse --policy prod.yaml # Grade the pulse with the thresholds in prod.yaml
+  kubectl pulse --cert-horizon 30d # Report certificates expiring within 30 days
+  kubectl pulse --exit-code    # Exit 0/1/2 for HEALTHY/WARNING/CRITICAL, 3 on errors
+  kubectl pulse -o nagios      # Run as a Nagios/Icinga check plugin
+  kubectl pulse serve          # Expose the pulse as Prometheus metrics on :9090`,
 	Run: func(cmd *cobra.Command, args []string) {
-		service, err := pulse.NewService()
+		ctx := cmd.Context()
+
+		policy, err := loadPolicy()
+		if err != nil {
+			fail("Error loading health policy", err)
+		}
+
+		if allContexts || len(contexts) > 0 {
+			runFleet(ctx, policy)
+			return
+		}
+		if watch {
+			checkInterval()
+		}
+
+		service, err := pulse.NewService(clientOptions)
 		if err != nil {
-			fmt.Printf("🚨 Error initializing pulse service: %v\n", err)
-			os.Exit(1)
+			fail("Error initializing pulse service", err)
 		}
+		service.SetPolicy(policy)
 
-		result, err := service.GetClusterPulse(minutes, podAmount, namespace)
+		if err := service.SetOutput(output); err != nil {
+			fail("Error", err)
+		}
+
+		if watch {
+			runWatch(ctx, service)
+			return
+		}
+
+		pulseCtx, cancel := withTimeout(ctx)
+		defer cancel()
+
+		health, err := service.GetClusterHealth(pulseCtx, minutes, podAmount, namespace)
 		if err != nil {
-			fmt.Printf("🚨 Error getting cluster pulse: %v\n", err)
-			os.Exit(1)
+			exitOnInterrupt(err)
+			fail("Error getting cluster pulse", err)
+		}
+
+		result, err := service.Format(health)
+		if err != nil {
+			fail("Error formatting cluster pulse", err)
 		}
 
 		fmt.Println(result)
+		if output == pulse.OutputNagios {
+			os.Exit(pulse.NagiosExitCode(health))
+		}
+		exitWithStatus(health.Status)
 	},
 }
 
+// fail reports err on stderr and exits with the error exit code. Monitoring
+// plugins report on stdout, so the Nagios output prints an UNKNOWN line there.
+func fail(message string, err error) {
+	if output == pulse.OutputNagios {
+		fmt.Printf("PULSE UNKNOWN - %s: %v\n", message, err)
+		os.Exit(pulse.ExitCodeError)
+	}
+
+	fmt.Fprintf(os.Stderr, "🚨 %s: %v\n", message, err)
+	if exitCode {
+		os.Exit(pulse.ExitCodeError)
+	}
+	os.Exit(1)
+}
+
+// exitWithStatus exits with the status' exit code when --exit-code is set or
+// the output is a Nagios check result.
+func exitWithStatus(status pulse.HealthStatus) {
+	if exitCode || output == pulse.OutputNagios {
+		os.Exit(status.ExitCode())
+	}
+}
+
+// loadPolicy reads the health policy and applies --cert-horizon to it.
+func loadPolicy() (*pulse.HealthPolicy, error) {
+	policy, err := readPolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	if certHorizon != "" {
+		horizon, err := pulse.ParseDuration(certHorizon)
+		if err != nil || horizon.Duration < 0 {
+			return nil, fmt.Errorf("invalid --cert-horizon %q, expected e.g. 30d or 36h", certHorizon)
+		}
+		policy.CertificateHorizon = horizon
+	}
+	return policy, nil
+}
+
+// readPolicy reads the health policy given with --policy, or the one in the
+// user's config directory if present, falling back to the built-in defaults.
+func readPolicy() (*pulse.HealthPolicy, error) {
+	if policyPath != "" {
+		return pulse.LoadHealthPolicy(policyPath)
+	}
+
+	path, err := pulse.DefaultHealthPolicyPath()
+	if err != nil {
+		return pulse.DefaultHealthPolicy(), nil
+	}
+	policy, err := pulse.LoadHealthPolicy(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return pulse.DefaultHealthPolicy(), nil
+	}
+	return policy, err
+}
+
+// checkInterval fails on an --interval the refresh ticker cannot run with.
+func checkInterval() {
+	if interval <= 0 {
+		fail("Invalid --interval", fmt.Errorf("--interval must be positive, got %s", interval))
+	}
+}
+
+// withTimeout bounds a single pulse by --timeout, if one was given.
+func withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// exitOnInterrupt exits quietly when err is the result of Ctrl-C.
+func exitOnInterrupt(err error) {
+	if errors.Is(err, context.Canceled) {
+		os.Exit(130)
+	}
+}
+
+func runWatch(ctx context.Context, service *pulse.Service) {
+	// Follow the cluster with watches rather than listing it on every refresh
+	syncCtx, cancel := withTimeout(ctx)
+	err := service.StartInformers(syncCtx, namespace)
+	cancel()
+	if err != nil {
+		exitOnInterrupt(err)
+		fail("Error starting informers", err)
+	}
+	defer service.StopInformers()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	watcher := pulse.NewWatcher(service)
+	for {
+		frameCtx, cancel := withTimeout(ctx)
+		result, err := watcher.Next(frameCtx, minutes, podAmount, namespace)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		switch output {
+		case pulse.OutputText, pulse.OutputMarkdown:
+			// Redraw in place: move the cursor home and clear the screen
+			fmt.Print("\033[H\033[2J")
+		case pulse.OutputYAML:
+			fmt.Println("---")
+		}
+		if err != nil {
+			// Keep watching through transient API errors
+			fmt.Fprintf(os.Stderr, "🚨 Error getting cluster pulse: %v\n", err)
+		} else {
+			fmt.Println(result)
+		}
+		if output == pulse.OutputText {
+			fmt.Printf("\n⏱️  %s - refreshing every %s, Ctrl-C to quit\n", time.Now().Format("15:04:05"), interval)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func runFleet(ctx context.Context, policy *pulse.HealthPolicy) {
+	if allContexts {
+		var err error
+		contexts, err = pulse.ListContexts(clientOptions)
+		if err != nil {
+			fail("Error reading kubeconfig contexts", err)
+		}
+	}
+
+	fleet := pulse.NewFleet(clientOptions, contexts)
+	fleet.SetPolicy(policy)
+	if err := fleet.SetOutput(output); err != nil {
+		fail("Error", err)
+	}
+
+	fleetCtx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	health := fleet.GetFleetHealth(fleetCtx, minutes, podAmount, namespace)
+	exitOnInterrupt(ctx.Err())
+
+	result, err := fleet.Format(health)
+	if err != nil {
+		fail("Error formatting fleet pulse", err)
+	}
+
+	fmt.Println(result)
+	exitWithStatus(health.Status)
+}
+
 func init() {
 	rootCmd.PersistentFlags().StringVarP(&namespace, "namespace", "n", "", "Namespace to check for restarts")
 	rootCmd.PersistentFlags().IntVarP(&minutes, "minutes", "m", 15, "Time window in minutes to check for restarts")
 	rootCmd.PersistentFlags().IntVarP(&podAmount, "pod-amount", "p", 3, "Amount of pods to check for restarts")
+	rootCmd.PersistentFlags().StringVarP(&output, "output", "o", pulse.OutputText, "Output format: "+strings.Join(pulse.FormatterNames(), ", "))
+	rootCmd.PersistentFlags().BoolVar(&exitCode, "exit-code", false, "Exit with 0, 1 or 2 for HEALTHY, WARNING or CRITICAL and 3 on errors")
+	rootCmd.PersistentFlags().StringVar(&certHorizon, "cert-horizon", "", "Report certificates expiring within this long, e.g. 30d or 36h (default 14d, or certificateHorizon in the policy)")
+	rootCmd.PersistentFlags().StringVar(&policyPath, "policy", "", "Health policy file with the thresholds for each status (default ~/.config/kubectl-pulse/policy.yaml if present)")
+
+	// kubectl global flags, so the plugin targets the same cluster as kubectl would
+	rootCmd.PersistentFlags().StringVar(&clientOptions.Kubeconfig, "kubeconfig", "", "Path to the kubeconfig file to use for CLI requests")
+	rootCmd.PersistentFlags().StringVar(&clientOptions.Context, "context", "", "The name of the kubeconfig context to use")
+	rootCmd.PersistentFlags().StringVar(&clientOptions.Cluster, "cluster", "", "The name of the kubeconfig cluster to use")
+	rootCmd.PersistentFlags().StringVar(&clientOptions.User, "user", "", "The name of the kubeconfig user to use")
+	rootCmd.PersistentFlags().StringVar(&clientOptions.Impersonate, "as", "", "Username to impersonate for the operation")
+	rootCmd.PersistentFlags().StringVar(&clientOptions.RequestTimeout, "request-timeout", "0", "The length of time to wait before giving up on a single server request (e.g. 1s, 2m). Zero means don't timeout requests")
+
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 0, "Give up on a pulse after this long and print the sections gathered so far (e.g. 10s). Zero means no timeout")
+
+	rootCmd.PersistentFlags().StringSliceVar(&contexts, "contexts", nil, "Comma-separated kubeconfig contexts to check concurrently")
+	rootCmd.PersistentFlags().BoolVar(&allContexts, "all-contexts", false, "Check every context in the kubeconfig concurrently")
+	rootCmd.MarkFlagsMutuallyExclusive("context", "contexts", "all-contexts")
+
+	rootCmd.PersistentFlags().BoolVarP(&watch, "watch", "w", false, "Keep refreshing the pulse and highlight changes between refreshes")
+	rootCmd.PersistentFlags().DurationVar(&interval, "interval", 5*time.Second, "Refresh interval in watch and serve mode")
+	rootCmd.MarkFlagsMutuallyExclusive("watch", "contexts")
+	rootCmd.MarkFlagsMutuallyExclusive("watch", "all-contexts")
+	rootCmd.MarkFlagsMutuallyExclusive("watch", "exit-code")
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	// Ctrl-C cancels in-flight API calls instead of killing the process mid-write
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
+		if exitCode {
+			os.Exit(pulse.ExitCodeError)
+		}
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stenstromen/kubectl-pulse/internal/pulse"
+)
+
+var listen string
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Expose the cluster pulse as Prometheus metrics",
+	Long: `Run the pulse every --interval and serve the latest result as Prometheus metrics on /metrics
+
+Example usage:
+  kubectl pulse serve                         # Serve metrics on :9090, refreshing every 5 seconds
+  kubectl pulse serve --listen :8080 -m 30    # Serve on :8080 with a 30-minute restart window
+  kubectl pulse serve --interval 30s -n prod  # Refresh every 30 seconds, limited to the prod namespace`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		checkInterval()
+
+		policy, err := loadPolicy()
+		if err != nil {
+			fail("Error loading health policy", err)
+		}
+
+		service, err := pulse.NewService(clientOptions)
+		if err != nil {
+			fail("Error initializing pulse service", err)
+		}
+		service.SetPolicy(policy)
+
+		// Follow the cluster with watches rather than listing it on every refresh
+		syncCtx, cancel := withTimeout(ctx)
+		err = service.StartInformers(syncCtx, namespace)
+		cancel()
+		if err != nil {
+			exitOnInterrupt(err)
+			fail("Error starting informers", err)
+		}
+		defer service.StopInformers()
+
+		exporter := pulse.NewExporter(service, minutes, podAmount, namespace)
+		go refreshExporter(ctx, exporter)
+
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", exporter.Handler())
+		server := &http.Server{
+			Addr:              listen,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = server.Shutdown(shutdownCtx)
+		}()
+
+		fmt.Fprintf(os.Stderr, "📡 Serving pulse metrics on %s/metrics, refreshing every %s\n", listen, interval)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fail("Error serving metrics", err)
+		}
+	},
+}
+
+// refreshExporter takes a pulse every --interval until ctx is done.
+func refreshExporter(ctx context.Context, exporter *pulse.Exporter) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		refreshCtx, cancel := withTimeout(ctx)
+		err := exporter.Refresh(refreshCtx)
+		cancel()
+		if err != nil && ctx.Err() == nil {
+			// Keep serving the previous pulse through transient API errors
+			fmt.Fprintf(os.Stderr, "🚨 Error getting cluster pulse: %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&listen, "listen", ":9090", "Address to serve metrics on")
+	rootCmd.AddCommand(serveCmd)
+}
@@ -1,42 +1,315 @@
 package pulse
 
 import (
+	"fmt"
 	"sort"
 	"time"
 )
 
-type Analyzer struct{}
+// rolloutStuckThreshold is how long the newest ReplicaSet of a Deployment may
+// have unavailable replicas before the rollout is reported as stuck.
+const rolloutStuckThreshold = 10 * time.Minute
+
+// rolloutLagGracePeriod is how long the Deployment controller may take to
+// observe a new generation, as after every apply, before the rollout is
+// reported as stuck.
+const rolloutLagGracePeriod = time.Minute
+
+// claimPendingThreshold is how long a PersistentVolumeClaim may be Pending
+// without a Warning event before it is reported, leaving time to provision.
+const claimPendingThreshold = 5 * time.Minute
+
+type Analyzer struct {
+	history *RestartHistory
+	policy  *HealthPolicy
+}
 
 func NewAnalyzer() *Analyzer {
-	return &Analyzer{}
+	return &Analyzer{
+		history: NewRestartHistory(""),
+		policy:  DefaultHealthPolicy(),
+	}
+}
+
+// NewAnalyzerWithHistory measures restarts within the time window against
+// restart counts sampled on earlier runs.
+func NewAnalyzerWithHistory(history *RestartHistory) *Analyzer {
+	return &Analyzer{
+		history: history,
+		policy:  DefaultHealthPolicy(),
+	}
+}
+
+// SetPolicy replaces the health policy statuses are graded with.
+func (a *Analyzer) SetPolicy(policy *HealthPolicy) {
+	a.policy = policy
 }
 
 func (a *Analyzer) AnalyzeClusterHealth(pods []PodStatus, timeWindowMinutes int, podAmount int, namespace string) ClusterHealth {
-	recentRestarts, recentRestartPods := a.countRecentRestarts(pods, time.Duration(timeWindowMinutes)*time.Minute, namespace)
+	recentRestarts, recentRestartPods, restartBaseline := a.countRecentRestarts(pods, time.Duration(timeWindowMinutes)*time.Minute, namespace)
 	topOffenders := a.getTopOffenders(pods, podAmount, namespace)
 	statusDistribution := a.calculatePodStatusDistribution(pods, namespace)
+	containerReasons := a.countContainerReasons(pods, time.Duration(timeWindowMinutes)*time.Minute, namespace)
+	namespacePods := a.countNamespacePods(pods, time.Duration(timeWindowMinutes)*time.Minute, namespace)
+	oomKills := a.findRecentOOMKills(pods, time.Duration(timeWindowMinutes)*time.Minute, namespace)
+	pendingPods := a.findPendingPods(pods, namespace)
 
-	return ClusterHealth{
+	health := ClusterHealth{
 		RecentRestarts:        recentRestarts,
 		RecentRestartPods:     recentRestartPods,
+		RestartBaseline:       restartBaseline,
 		TopOffenders:          topOffenders,
 		PodStatusDistribution: statusDistribution,
+		ContainerReasons:      containerReasons,
+		NamespacePods:         namespacePods,
+		OOMKills:              oomKills,
+		PendingPods:           pendingPods,
 		TimeWindow:            timeWindowMinutes,
 	}
+	health.Signals = a.GradeSignals(health)
+	health.Status = a.DetermineStatus(health)
+
+	return health
 }
 
-func (a *Analyzer) countRecentRestarts(pods []PodStatus, window time.Duration, namespace string) (int, []PodStatus) {
+// DetermineStatus derives the overall verdict from every section present in
+// health by grading it against the health policy. Call it again after
+// attaching additional sections.
+func (a *Analyzer) DetermineStatus(health ClusterHealth) HealthStatus {
+	status := StatusHealthy
+	for _, signal := range a.GradeSignals(health) {
+		status = worseStatus(status, signal.Status)
+	}
+	return status
+}
+
+// GradeSignals counts every signal of the health policy in health and grades
+// it. Namespaces with a policy override are graded on their own; all others
+// are counted together against the cluster-wide thresholds. Node signals are
+// only present when health has a node section.
+func (a *Analyzer) GradeSignals(health ClusterHealth) []Signal {
+	groups := make(map[string]map[string]int)
+	add := func(namespace string, signal string, n int) {
+		group := a.policy.group(namespace)
+		if groups[group] == nil {
+			groups[group] = make(map[string]int)
+		}
+		groups[group][signal] += n
+	}
+
+	for _, pod := range health.RecentRestartPods {
+		add(pod.Namespace, SignalRestarts, int(pod.RecentRestarts))
+	}
+	for namespace, pods := range health.NamespacePods {
+		add(namespace, SignalPendingPods, pods.Pending)
+		add(namespace, SignalFailedPods, pods.Failed)
+		add(namespace, SignalContainerReasons, pods.ContainerReasons)
+	}
+	for _, kill := range health.OOMKills {
+		add(kill.Namespace, SignalOOMKills, int(kill.Kills))
+	}
+	for _, workload := range health.DegradedWorkloads {
+		add(workload.Namespace, SignalDegradedWorkloads, 1)
+		if workload.Available == 0 {
+			add(workload.Namespace, SignalUnavailableWorkloads, 1)
+		}
+	}
+	for _, rollout := range health.StuckRollouts {
+		add(rollout.Namespace, SignalStuckRollouts, 1)
+	}
+	for _, autoscaler := range health.Autoscalers {
+		add(autoscaler.Namespace, SignalAutoscalerIssues, 1)
+	}
+	for _, service := range health.UnhealthyServices {
+		add(service.Namespace, SignalDegradedServices, 1)
+		if service.ReadyEndpoints == 0 {
+			add(service.Namespace, SignalUnavailableServices, 1)
+		}
+	}
+	for _, reason := range health.WarningEvents {
+		for _, object := range reason.Objects {
+			add(object.Namespace, SignalWarningEvents, object.Count)
+		}
+	}
+	for _, issue := range health.RouteIssues {
+		add(issue.Namespace, SignalRouteIssues, 1)
+	}
+	if certificates := health.Certificates; certificates != nil {
+		for _, issue := range certificates.Issues {
+			if issue.Status == CertificateExpired {
+				add(issue.Namespace, SignalExpiredCertificates, 1)
+			} else {
+				add(issue.Namespace, SignalExpiringCertificates, 1)
+			}
+		}
+	}
+	for _, issue := range health.StorageIssues {
+		add(issue.Namespace, SignalStorageIssues, 1)
+	}
+
+	thresholds := a.policy.bySignal()
+	groupThresholds := make(map[string]map[string]Threshold, len(groups))
+	for group := range groups {
+		groupThresholds[group] = a.policy.For(group).bySignal()
+	}
+
+	var signals []Signal
+	for _, name := range namespacedSignals {
+		signal := Signal{
+			Name:     name,
+			Warning:  thresholds[name].Warning,
+			Critical: thresholds[name].Critical,
+			Status:   StatusHealthy,
+		}
+		for group, counts := range groups {
+			signal.Value += counts[name]
+			signal.Status = worseStatus(signal.Status, groupThresholds[group][name].Evaluate(counts[name]))
+		}
+		signals = append(signals, signal)
+	}
+
+	if nodes := health.Nodes; nodes != nil {
+		pressured := make(map[string]bool)
+		for _, names := range [][]string{nodes.MemoryPressure, nodes.DiskPressure, nodes.PIDPressure, nodes.NetworkUnavailable} {
+			for _, name := range names {
+				pressured[name] = true
+			}
+		}
+
+		for _, signal := range []Signal{
+			{Name: SignalNotReadyNodes, Value: nodes.NotReady + nodes.Unknown},
+			{Name: SignalPressureNodes, Value: len(pressured)},
+		} {
+			signal.Warning = thresholds[signal.Name].Warning
+			signal.Critical = thresholds[signal.Name].Critical
+			signal.Status = thresholds[signal.Name].Evaluate(signal.Value)
+			signals = append(signals, signal)
+		}
+	}
+
+	// A section that timed out may hide problems, so a pulse without it is
+	// not an all-clear
+	incomplete := thresholds[SignalIncompleteSections]
+	signals = append(signals, Signal{
+		Name:     SignalIncompleteSections,
+		Value:    len(health.Incomplete),
+		Warning:  incomplete.Warning,
+		Critical: incomplete.Critical,
+		Status:   incomplete.Evaluate(len(health.Incomplete)),
+	})
+
+	return signals
+}
+
+func worseStatus(a, b HealthStatus) HealthStatus {
+	if b.severity() > a.severity() {
+		return b
+	}
+	return a
+}
+
+// AnalyzeNodeHealth buckets nodes by their Ready condition and collects the
+// ones that are cordoned or reporting resource pressure.
+func (a *Analyzer) AnalyzeNodeHealth(nodes []NodeStatus) *NodeHealth {
+	health := &NodeHealth{}
+
+	for _, node := range nodes {
+		health.Total++
+		switch node.Ready {
+		case "True":
+			health.Ready++
+		case "False":
+			health.NotReady++
+			health.NotReadyNodes = append(health.NotReadyNodes, node.Name)
+		default:
+			health.Unknown++
+			health.NotReadyNodes = append(health.NotReadyNodes, node.Name)
+		}
+
+		if node.Unschedulable {
+			health.SchedulingDisabled++
+			health.CordonedNodes = append(health.CordonedNodes, node.Name)
+		}
+
+		for _, condition := range node.Pressure {
+			switch condition {
+			case "MemoryPressure":
+				health.MemoryPressure = append(health.MemoryPressure, node.Name)
+			case "DiskPressure":
+				health.DiskPressure = append(health.DiskPressure, node.Name)
+			case "PIDPressure":
+				health.PIDPressure = append(health.PIDPressure, node.Name)
+			case "NetworkUnavailable":
+				health.NetworkUnavailable = append(health.NetworkUnavailable, node.Name)
+			}
+		}
+	}
+
+	return health
+}
+
+// countRecentRestarts totals the restarts within the window and returns the
+// pods that restarted, most restarts first, along with the oldest history
+// sample used as a baseline.
+func (a *Analyzer) countRecentRestarts(pods []PodStatus, window time.Duration, namespace string) (int, []PodStatus, time.Time) {
 	var recentRestartPods []PodStatus
+	var total int
+	var baseline time.Time
 	now := time.Now()
+
 	for _, pod := range pods {
 		if namespace != "" && pod.Namespace != namespace {
 			continue
 		}
-		if !pod.LastRestart.IsZero() && now.Sub(pod.LastRestart) <= window {
+
+		restarts, sampledAt := a.estimateRecentRestarts(pod, window, now)
+		if !sampledAt.IsZero() && (baseline.IsZero() || sampledAt.Before(baseline)) {
+			baseline = sampledAt
+		}
+		if restarts > 0 {
+			pod.RecentRestarts = restarts
 			recentRestartPods = append(recentRestartPods, pod)
+			total += int(restarts)
 		}
 	}
-	return len(recentRestartPods), recentRestartPods
+
+	sort.SliceStable(recentRestartPods, func(i, j int) bool {
+		return recentRestartPods[i].RecentRestarts > recentRestartPods[j].RecentRestarts
+	})
+
+	return total, recentRestartPods, baseline
+}
+
+// estimateRecentRestarts returns how often the pod restarted within the
+// window and the time of the history sample that was diffed against.
+//
+// With a sample giving the count at the window start, see Baseline, the
+// difference in restart counts is exact up to the restarts in the slack
+// before the window. Without one, the lower bound is every restart for a pod
+// created inside the window, or a single restart if the last one falls
+// inside it.
+func (a *Analyzer) estimateRecentRestarts(pod PodStatus, window time.Duration, now time.Time) (int32, time.Time) {
+	var lowerBound int32
+	if !pod.Created.IsZero() && now.Sub(pod.Created) <= window {
+		lowerBound = pod.Restarts
+	} else if !pod.LastRestart.IsZero() && now.Sub(pod.LastRestart) <= window {
+		lowerBound = 1
+	}
+
+	sample, covered, ok := a.history.Baseline(pod.UID, window, now)
+	if !ok {
+		return lowerBound, time.Time{}
+	}
+
+	delta := pod.Restarts - sample.Restarts
+	if delta < 0 {
+		// Counts only go down when the kubelet lost its state; start over
+		delta = pod.Restarts
+	}
+	if covered {
+		return delta, sample.Time
+	}
+	return max(delta, lowerBound), sample.Time
 }
 
 func (a *Analyzer) getTopOffenders(pods []PodStatus, limit int, namespace string) []PodStatus {
@@ -52,11 +325,131 @@ func (a *Analyzer) getTopOffenders(pods []PodStatus, limit int, namespace string
 	})
 
 	if len(filteredPods) > limit {
-		return filteredPods[:limit]
+		filteredPods = filteredPods[:limit]
+	}
+	for i, pod := range filteredPods {
+		filteredPods[i].Severity = a.policy.For(pod.Namespace).OffenderRestarts.Evaluate(int(pod.Restarts))
 	}
 	return filteredPods
 }
 
+// benignReasons are container reasons that are part of a normal pod lifecycle.
+var benignReasons = map[string]bool{
+	"Completed":         true,
+	"ContainerCreating": true,
+	"PodInitializing":   true,
+}
+
+// countContainerReasons builds a histogram of problem reasons. Waiting reasons
+// always count since they describe the present; termination reasons only count
+// when the container finished within the window.
+func (a *Analyzer) countContainerReasons(pods []PodStatus, window time.Duration, namespace string) map[string]int {
+	counts := make(map[string]int)
+	now := time.Now()
+
+	for _, pod := range pods {
+		if namespace != "" && pod.Namespace != namespace {
+			continue
+		}
+
+		for _, reason := range pod.Reasons {
+			if isProblemReason(reason, window, now) {
+				counts[reason.Reason]++
+			}
+		}
+	}
+
+	return counts
+}
+
+func isProblemReason(reason ContainerReason, window time.Duration, now time.Time) bool {
+	if benignReasons[reason.Reason] {
+		return false
+	}
+	return reason.FinishedAt.IsZero() || now.Sub(reason.FinishedAt) <= window
+}
+
+// countNamespacePods breaks the pod signals graded by the health policy down
+// per namespace.
+func (a *Analyzer) countNamespacePods(pods []PodStatus, window time.Duration, namespace string) map[string]NamespacePods {
+	counts := make(map[string]NamespacePods)
+	now := time.Now()
+
+	for _, pod := range pods {
+		if namespace != "" && pod.Namespace != namespace {
+			continue
+		}
+
+		c := counts[pod.Namespace]
+		switch pod.Status {
+		case "Pending":
+			c.Pending++
+		case "Failed":
+			c.Failed++
+		}
+		// A container can report a problem in both its current and its last
+		// state, so count containers rather than reasons.
+		problems := make(map[string]bool)
+		for _, reason := range pod.Reasons {
+			if isProblemReason(reason, window, now) {
+				problems[reason.Container] = true
+			}
+		}
+		c.ContainerReasons += len(problems)
+		counts[pod.Namespace] = c
+	}
+
+	return counts
+}
+
+// findRecentOOMKills returns the OOM kills that happened within the window,
+// most recent first. Each is given an estimate of how often it happened in
+// the window: the pod's restarts within it, capped at the container's own
+// restarts and counting the last kill at least.
+func (a *Analyzer) findRecentOOMKills(pods []PodStatus, window time.Duration, namespace string) []OOMKill {
+	var kills []OOMKill
+	now := time.Now()
+
+	for _, pod := range pods {
+		if namespace != "" && pod.Namespace != namespace {
+			continue
+		}
+		restarts, _ := a.estimateRecentRestarts(pod, window, now)
+		for _, kill := range pod.OOMKills {
+			if now.Sub(kill.KilledAt) <= window {
+				kill.Kills = max(min(restarts, kill.Restarts), 1)
+				kills = append(kills, kill)
+			}
+		}
+	}
+
+	sort.Slice(kills, func(i, j int) bool {
+		return kills[i].KilledAt.After(kills[j].KilledAt)
+	})
+
+	return kills
+}
+
+// findPendingPods returns the diagnosis of every Pending pod, longest pending
+// first.
+func (a *Analyzer) findPendingPods(pods []PodStatus, namespace string) []PendingPod {
+	var pending []PendingPod
+	for _, pod := range pods {
+		if namespace != "" && pod.Namespace != namespace {
+			continue
+		}
+		if pod.Pending != nil {
+			pending = append(pending, *pod.Pending)
+		}
+	}
+
+	sort.SliceStable(pending, func(i, j int) bool {
+		return pending[i].Since.Before(pending[j].Since)
+	})
+
+	return pending
+}
+
 func (a *Analyzer) calculatePodStatusDistribution(pods []PodStatus, namespace string) PodStatusDistribution {
 	distribution := PodStatusDistribution{}
 
@@ -82,3 +475,618 @@ func (a *Analyzer) calculatePodStatusDistribution(pods []PodStatus, namespace st
 
 	return distribution
 }
+
+// AnalyzeWorkloads returns the workloads with fewer ready or available
+// replicas than desired, sorted by namespace, kind and name.
+func (a *Analyzer) AnalyzeWorkloads(workloads []WorkloadStatus) []WorkloadStatus {
+	var degraded []WorkloadStatus
+	for _, workload := range workloads {
+		if workload.Ready < workload.Desired || workload.Available < workload.Desired {
+			degraded = append(degraded, workload)
+		}
+	}
+
+	sort.Slice(degraded, func(i, j int) bool {
+		if degraded[i].Namespace != degraded[j].Namespace {
+			return degraded[i].Namespace < degraded[j].Namespace
+		}
+		if degraded[i].Kind != degraded[j].Kind {
+			return degraded[i].Kind < degraded[j].Kind
+		}
+		return degraded[i].Name < degraded[j].Name
+	})
+
+	return degraded
+}
+
+// AnalyzeRollouts flags Deployments whose rollout has exceeded its progress
+// deadline, whose spec has not been observed by the controller for longer
+// than rolloutLagGracePeriod, or whose in-progress ReplicaSet has been short
+// of available replicas for too long. A lag can only be told apart from a
+// fresh apply across runs, so when it was first seen is kept in the history,
+// which lets repeated one-shot runs report it as well as watch mode.
+func (a *Analyzer) AnalyzeRollouts(rollouts []RolloutStatus) []StuckRollout {
+	var stuck []StuckRollout
+	now := time.Now()
+	lags := a.history.TrackGenerationLags(rollouts, now)
+
+	for _, rollout := range rollouts {
+		result := StuckRollout{
+			Name:       rollout.Name,
+			Namespace:  rollout.Namespace,
+			Revision:   rollout.Revision,
+			ReplicaSet: rollout.ReplicaSet,
+		}
+		lag, lagging := lags[rollout.Namespace+"/"+rollout.Name]
+
+		switch {
+		case rollout.ProgressReason == "ProgressDeadlineExceeded":
+			result.Reason = "ProgressDeadlineExceeded"
+			result.Message = rollout.ProgressMessage
+		case lagging && now.Sub(lag.Since) > rolloutLagGracePeriod:
+			result.Reason = "ObservedGenerationLag"
+			result.Message = fmt.Sprintf("controller observed generation %d of %d for %s",
+				rollout.ObservedGeneration, rollout.Generation, now.Sub(lag.Since).Round(time.Second))
+		case rollout.ProgressReason != "NewReplicaSetAvailable" && rollout.ReplicaSet != "" &&
+			rollout.ReplicaSetAvailable < rollout.ReplicaSetDesired && now.Sub(rollout.ReplicaSetCreated) > rolloutStuckThreshold:
+			result.Reason = "ReplicaSetUnavailable"
+			result.Message = fmt.Sprintf("%d/%d replicas available after %s",
+				rollout.ReplicaSetAvailable, rollout.ReplicaSetDesired, now.Sub(rollout.ReplicaSetCreated).Round(time.Minute))
+		default:
+			continue
+		}
+
+		stuck = append(stuck, result)
+	}
+
+	sort.Slice(stuck, func(i, j int) bool {
+		if stuck[i].Namespace != stuck[j].Namespace {
+			return stuck[i].Namespace < stuck[j].Namespace
+		}
+		return stuck[i].Name < stuck[j].Name
+	})
+
+	return stuck
+}
+
+// AnalyzeAutoscalers returns the HorizontalPodAutoscalers pinned at their
+// maxReplicas, unable to compute a scale (ScalingActive=False) or limited by
+// their maxReplicas or scale rate (ScalingLimited=True), sorted by namespace
+// and name. Autoscalers whose target was scaled to zero have scaling disabled
+// on purpose and are not reported as inactive, and autoscalers held at their
+// minReplicas (TooFewReplicas) are idle, not limited. Autoscalers with
+// minReplicas equal to maxReplicas always run at their maximum, so that is
+// not reported either.
+func (a *Analyzer) AnalyzeAutoscalers(autoscalers []AutoscalerStatus) []AutoscalerStatus {
+	var flagged []AutoscalerStatus
+	for _, autoscaler := range autoscalers {
+		autoscaler.Problems = nil
+		autoscaler.Message = ""
+
+		if autoscaler.MaxReplicas > autoscaler.MinReplicas && autoscaler.CurrentReplicas >= autoscaler.MaxReplicas {
+			autoscaler.Problems = append(autoscaler.Problems, AutoscalerAtMaxReplicas)
+		}
+		if autoscaler.ScalingLimited == "True" && autoscaler.ScalingLimitedReason != "TooFewReplicas" {
+			autoscaler.Problems = append(autoscaler.Problems, AutoscalerScalingLimited)
+			autoscaler.Message = conditionMessage(autoscaler.ScalingLimitedReason, autoscaler.ScalingLimitedMessage)
+		}
+		if autoscaler.ScalingActive == "False" && autoscaler.ScalingActiveReason != "ScalingDisabled" {
+			autoscaler.Problems = append(autoscaler.Problems, AutoscalerScalingInactive)
+			autoscaler.Message = conditionMessage(autoscaler.ScalingActiveReason, autoscaler.ScalingActiveMessage)
+		}
+
+		if len(autoscaler.Problems) > 0 {
+			flagged = append(flagged, autoscaler)
+		}
+	}
+
+	sort.Slice(flagged, func(i, j int) bool {
+		if flagged[i].Namespace != flagged[j].Namespace {
+			return flagged[i].Namespace < flagged[j].Namespace
+		}
+		return flagged[i].Name < flagged[j].Name
+	})
+
+	return flagged
+}
+
+// conditionMessage joins a condition's reason and message.
+func conditionMessage(reason, message string) string {
+	if message == "" {
+		return reason
+	}
+	return reason + " - " + message
+}
+
+// AnalyzeServices returns the services with no ready endpoints, or with
+// fewer than half of their endpoints ready, sorted by namespace and name.
+// Each is given the number of running or pending pods its selector matches,
+// which tells a selector matching nothing from backends that are not ready.
+// ExternalName services and services without a selector have no endpoints
+// Kubernetes manages, so they are not judged.
+func (a *Analyzer) AnalyzeServices(services []ServiceStatus, pods []PodStatus) []ServiceStatus {
+	var unhealthy []ServiceStatus
+	for _, service := range services {
+		if !managesEndpoints(service) {
+			continue
+		}
+		if service.ReadyEndpoints > 0 && service.ReadyEndpoints*2 >= service.Endpoints {
+			continue
+		}
+
+		service.Pods = 0
+		for _, pod := range pods {
+			if pod.Namespace == service.Namespace && (pod.Status == "Running" || pod.Status == "Pending") && selects(service.Selector, pod.Labels) {
+				service.Pods++
+			}
+		}
+		unhealthy = append(unhealthy, service)
+	}
+
+	sort.Slice(unhealthy, func(i, j int) bool {
+		if unhealthy[i].Namespace != unhealthy[j].Namespace {
+			return unhealthy[i].Namespace < unhealthy[j].Namespace
+		}
+		return unhealthy[i].Name < unhealthy[j].Name
+	})
+
+	return unhealthy
+}
+
+// AnalyzeRoutes checks the backends of Ingresses and HTTPRoutes against the
+// services and reports HTTPRoutes a Gateway did not accept or could not
+// resolve the references of. With a namespace, backends in other namespaces
+// are not checked as their services were not read.
+func (a *Analyzer) AnalyzeRoutes(routes []RouteStatus, services []ServiceStatus, namespace string) []RouteIssue {
+	type serviceKey struct {
+		namespace, name string
+	}
+	byName := make(map[serviceKey]ServiceStatus, len(services))
+	for _, service := range services {
+		byName[serviceKey{service.Namespace, service.Name}] = service
+	}
+
+	var issues []RouteIssue
+	for _, route := range routes {
+		seen := make(map[RouteBackend]bool)
+		for _, backend := range route.Backends {
+			if seen[backend] || (namespace != "" && backend.Namespace != namespace) {
+				continue
+			}
+			seen[backend] = true
+
+			issue := RouteIssue{
+				Kind:      route.Kind,
+				Namespace: route.Namespace,
+				Name:      route.Name,
+				Backend:   routeBackendName(route, backend),
+			}
+			service, ok := byName[serviceKey{backend.Namespace, backend.Service}]
+			switch {
+			case !ok:
+				issue.Reason = RouteServiceNotFound
+				issue.Message = fmt.Sprintf("service %s not found", backend.Service)
+			case service.Type == "ExternalName":
+				// Resolved by DNS, there are no ports or endpoints to check
+				continue
+			case !exposesPort(service, backend):
+				issue.Reason = RoutePortNotFound
+				issue.Message = fmt.Sprintf("service %s does not expose port %s", backend.Service, routeBackendPort(backend))
+			case service.ReadyEndpoints == 0:
+				issue.Reason = RouteNoReadyEndpoints
+				issue.Message = fmt.Sprintf("service %s has %d endpoints, none ready", backend.Service, service.Endpoints)
+			default:
+				continue
+			}
+			issues = append(issues, issue)
+		}
+
+		for _, condition := range route.Conditions {
+			if condition.Status != "False" {
+				continue
+			}
+			issue := RouteIssue{
+				Kind:      route.Kind,
+				Namespace: route.Namespace,
+				Name:      route.Name,
+				Message:   fmt.Sprintf("%s: %s", condition.Parent, condition.Reason),
+			}
+			switch condition.Type {
+			case "Accepted":
+				issue.Reason = RouteNotAccepted
+			case "ResolvedRefs":
+				issue.Reason = RouteRefsNotResolved
+			default:
+				continue
+			}
+			if condition.Message != "" {
+				issue.Message += " - " + condition.Message
+			}
+			issues = append(issues, issue)
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Namespace != issues[j].Namespace {
+			return issues[i].Namespace < issues[j].Namespace
+		}
+		if issues[i].Kind != issues[j].Kind {
+			return issues[i].Kind < issues[j].Kind
+		}
+		return issues[i].Name < issues[j].Name
+	})
+
+	return issues
+}
+
+// exposesPort reports whether service has the port backend refers to. A
+// backend without a port leaves the choice to the service.
+func exposesPort(service ServiceStatus, backend RouteBackend) bool {
+	if backend.Port == 0 && backend.PortName == "" {
+		return true
+	}
+	for _, port := range service.Ports {
+		if (backend.Port != 0 && port.Port == backend.Port) || (backend.PortName != "" && port.Name == backend.PortName) {
+			return true
+		}
+	}
+	return false
+}
+
+// routeBackendName renders backend as service:port, prefixed with its
+// namespace when it is not the route's.
+func routeBackendName(route RouteStatus, backend RouteBackend) string {
+	name := backend.Service
+	if backend.Namespace != route.Namespace {
+		name = backend.Namespace + "/" + name
+	}
+	if port := routeBackendPort(backend); port != "" {
+		name += ":" + port
+	}
+	return name
+}
+
+func routeBackendPort(backend RouteBackend) string {
+	if backend.PortName != "" {
+		return backend.PortName
+	}
+	if backend.Port != 0 {
+		return fmt.Sprint(backend.Port)
+	}
+	return ""
+}
+
+// AnalyzeCertificates reports certificates that have expired, expire within
+// the policy's certificate horizon, are failing to renew (a cert-manager
+// Certificate that is not Ready) or cannot be parsed. A Secret managed by a
+// Certificate is left to the Certificate, which carries its renewal status.
+// Issues are sorted by expiry, soonest first.
+func (a *Analyzer) AnalyzeCertificates(inventory CertificateInventory) *CertificateHealth {
+	health := &CertificateHealth{
+		Checked:          len(inventory.Certificates),
+		Horizon:          a.policy.CertificateHorizon,
+		SecretsForbidden: inventory.SecretsForbidden,
+	}
+
+	managed := make(map[string]bool)
+	for _, certificate := range inventory.Certificates {
+		if certificate.Kind == "Certificate" && certificate.SecretName != "" {
+			managed[certificate.Namespace+"/"+certificate.SecretName] = true
+		}
+	}
+
+	now := time.Now()
+	for _, certificate := range inventory.Certificates {
+		if certificate.Kind == "Secret" && managed[certificate.Namespace+"/"+certificate.Name] {
+			health.Checked--
+			continue
+		}
+
+		issue := CertificateIssue{
+			Kind:      certificate.Kind,
+			Namespace: certificate.Namespace,
+			Name:      certificate.Name,
+			Subject:   certificate.Subject,
+			NotAfter:  certificate.NotAfter,
+		}
+		switch {
+		case certificate.Error != "":
+			issue.Status = CertificateInvalid
+			issue.Message = certificate.Error
+		case !certificate.NotAfter.IsZero() && !certificate.NotAfter.After(now):
+			issue.Status = CertificateExpired
+		case certificate.Ready == "False":
+			issue.Status = CertificateNotReady
+			issue.Message = certificate.Reason
+			if certificate.Message != "" {
+				issue.Message += " - " + certificate.Message
+			}
+		case !certificate.NotAfter.IsZero() && certificate.NotAfter.Sub(now) <= health.Horizon.Duration:
+			issue.Status = CertificateExpiring
+		default:
+			continue
+		}
+		health.Issues = append(health.Issues, issue)
+	}
+
+	// Certificates without a known expiry sort last
+	sort.Slice(health.Issues, func(i, j int) bool {
+		x, y := health.Issues[i], health.Issues[j]
+		if x.NotAfter.IsZero() != y.NotAfter.IsZero() {
+			return y.NotAfter.IsZero()
+		}
+		if !x.NotAfter.Equal(y.NotAfter) {
+			return x.NotAfter.Before(y.NotAfter)
+		}
+		if x.Namespace != y.Namespace {
+			return x.Namespace < y.Namespace
+		}
+		return x.Name < y.Name
+	})
+
+	return health
+}
+
+// managesEndpoints reports whether Kubernetes maintains the endpoints of
+// service from its selector.
+func managesEndpoints(service ServiceStatus) bool {
+	return service.Type != "ExternalName" && len(service.Selector) > 0
+}
+
+// selects reports whether every label of selector is set on labels.
+func selects(selector, labels map[string]string) bool {
+	for key, value := range selector {
+		if actual, ok := labels[key]; !ok || actual != value {
+			return false
+		}
+	}
+	return true
+}
+
+// notableNormalEvents are reasons reported as Normal events that still
+// explain why a cluster is unhealthy.
+var notableNormalEvents = map[string]bool{
+	"NodeNotReady": true,
+}
+
+// AnalyzeEvents groups the Warning events last seen within the window by
+// reason, along with the objects they are about. An event counts every
+// occurrence deduplicated into it, since the API does not record when the
+// earlier ones happened.
+func (a *Analyzer) AnalyzeEvents(events []Event, timeWindowMinutes int) []EventReason {
+	type objectKey struct {
+		reason, kind, namespace, name string
+	}
+
+	window := time.Duration(timeWindowMinutes) * time.Minute
+	now := time.Now()
+	reasons := make(map[string]*EventReason)
+	objects := make(map[objectKey]*EventObject)
+	var order []objectKey
+
+	for _, event := range events {
+		if event.Type != "Warning" && !notableNormalEvents[event.Reason] {
+			continue
+		}
+		if now.Sub(event.LastSeen) > window {
+			continue
+		}
+
+		reason := reasons[event.Reason]
+		if reason == nil {
+			reason = &EventReason{Reason: event.Reason}
+			reasons[event.Reason] = reason
+		}
+		reason.Count += int(event.Count)
+
+		key := objectKey{event.Reason, event.Kind, event.Namespace, event.Name}
+		object := objects[key]
+		if object == nil {
+			object = &EventObject{Kind: event.Kind, Namespace: event.Namespace, Name: event.Name}
+			objects[key] = object
+			order = append(order, key)
+		}
+		object.Count += int(event.Count)
+		if event.LastSeen.After(object.LastSeen) {
+			object.LastSeen = event.LastSeen
+			object.Message = event.Message
+		}
+	}
+
+	for _, key := range order {
+		reasons[key.reason].Objects = append(reasons[key.reason].Objects, *objects[key])
+	}
+
+	summary := make([]EventReason, 0, len(reasons))
+	for _, reason := range reasons {
+		sort.Slice(reason.Objects, func(i, j int) bool {
+			if reason.Objects[i].Count != reason.Objects[j].Count {
+				return reason.Objects[i].Count > reason.Objects[j].Count
+			}
+			return reason.Objects[i].LastSeen.After(reason.Objects[j].LastSeen)
+		})
+		summary = append(summary, *reason)
+	}
+	sort.Slice(summary, func(i, j int) bool {
+		if summary[i].Count != summary[j].Count {
+			return summary[i].Count > summary[j].Count
+		}
+		return summary[i].Reason < summary[j].Reason
+	})
+
+	return summary
+}
+
+// DiffPods compares the pods of two successive pulses and reports pods that
+// appeared, disappeared, changed phase or restarted in between.
+func (a *Analyzer) DiffPods(previous, current []PodStatus, namespace string) []PodChange {
+	type podKey struct {
+		namespace, name string
+	}
+
+	before := make(map[podKey]PodStatus, len(previous))
+	for _, pod := range previous {
+		if namespace == "" || pod.Namespace == namespace {
+			before[podKey{pod.Namespace, pod.Name}] = pod
+		}
+	}
+
+	var changes []PodChange
+	for _, pod := range current {
+		if namespace != "" && pod.Namespace != namespace {
+			continue
+		}
+
+		key := podKey{pod.Namespace, pod.Name}
+		old, ok := before[key]
+		delete(before, key)
+
+		if !ok {
+			changes = append(changes, PodChange{Namespace: pod.Namespace, Pod: pod.Name, Change: ChangePodCreated, Detail: pod.Status})
+			continue
+		}
+		if old.Status != pod.Status {
+			changes = append(changes, PodChange{Namespace: pod.Namespace, Pod: pod.Name, Change: ChangePhaseChanged, Detail: old.Status + " → " + pod.Status})
+		}
+		if pod.Restarts > old.Restarts {
+			changes = append(changes, PodChange{Namespace: pod.Namespace, Pod: pod.Name, Change: ChangeRestarted, Detail: fmt.Sprintf("+%d restarts", pod.Restarts-old.Restarts)})
+		}
+	}
+
+	for key := range before {
+		changes = append(changes, PodChange{Namespace: key.namespace, Pod: key.name, Change: ChangePodDeleted})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Namespace != changes[j].Namespace {
+			return changes[i].Namespace < changes[j].Namespace
+		}
+		if changes[i].Pod != changes[j].Pod {
+			return changes[i].Pod < changes[j].Pod
+		}
+		return changes[i].Change < changes[j].Change
+	})
+
+	return changes
+}
+
+// volumeEventReasons are the pod event reasons of volumes failing to attach
+// or mount.
+var volumeEventReasons = map[string]bool{
+	"FailedAttachVolume": true,
+	"FailedMount":        true,
+}
+
+// AnalyzeStorage flags claims that are Lost or stuck Pending, volumes that are
+// Failed or Released, attachments with errors and, from the Warning events,
+// pods whose volumes fail to attach or mount. With a namespace, volumes and
+// attachments are limited to the ones bound to claims in it.
+func (a *Analyzer) AnalyzeStorage(storage StorageStatus, events []EventReason, namespace string) []StorageIssue {
+	// The latest Warning event of a claim explains why it is stuck, e.g.
+	// ProvisioningFailed
+	claimEvents := make(map[string]EventObject)
+	var issues []StorageIssue
+	for _, reason := range events {
+		for _, object := range reason.Objects {
+			switch {
+			case object.Kind == "PersistentVolumeClaim":
+				key := object.Namespace + "/" + object.Name
+				if object.LastSeen.After(claimEvents[key].LastSeen) {
+					claimEvents[key] = object
+				}
+			case object.Kind == "Pod" && volumeEventReasons[reason.Reason]:
+				issues = append(issues, StorageIssue{
+					Kind:      object.Kind,
+					Namespace: object.Namespace,
+					Name:      object.Name,
+					Status:    reason.Reason,
+					Message:   object.Message,
+				})
+			}
+		}
+	}
+
+	now := time.Now()
+	for _, claim := range storage.Claims {
+		event, hasEvent := claimEvents[claim.Namespace+"/"+claim.Name]
+		issue := StorageIssue{
+			Kind:      "PersistentVolumeClaim",
+			Namespace: claim.Namespace,
+			Name:      claim.Name,
+			Status:    claim.Phase,
+			Message:   event.Message,
+		}
+
+		switch claim.Phase {
+		case "Pending":
+			if claim.WaitingForConsumer || (!hasEvent && now.Sub(claim.Created) <= claimPendingThreshold) {
+				continue
+			}
+			issue.Since = claim.Created
+		case "Lost":
+			if issue.Message == "" {
+				issue.Message = "bound volume no longer exists"
+			}
+		default:
+			continue
+		}
+		issues = append(issues, issue)
+	}
+
+	inNamespace := make(map[string]bool)
+	for _, volume := range storage.Volumes {
+		if namespace != "" && volume.ClaimNamespace != namespace {
+			continue
+		}
+		inNamespace[volume.Name] = true
+
+		issue := StorageIssue{
+			Kind:    "PersistentVolume",
+			Name:    volume.Name,
+			Status:  volume.Phase,
+			Message: volume.Message,
+		}
+		switch volume.Phase {
+		case "Failed":
+		case "Released":
+			if issue.Message == "" {
+				issue.Message = fmt.Sprintf("claim %s/%s was deleted, reclaim policy %s", volume.ClaimNamespace, volume.Claim, volume.ReclaimPolicy)
+			}
+		default:
+			continue
+		}
+		issues = append(issues, issue)
+	}
+
+	for _, attachment := range storage.Attachments {
+		if namespace != "" && !inNamespace[attachment.Volume] {
+			continue
+		}
+		for _, failure := range []struct{ status, message string }{
+			{StorageAttachError, attachment.AttachError},
+			{StorageDetachError, attachment.DetachError},
+		} {
+			if failure.message == "" {
+				continue
+			}
+			issues = append(issues, StorageIssue{
+				Kind:    "VolumeAttachment",
+				Name:    attachment.Name,
+				Status:  failure.status,
+				Message: fmt.Sprintf("%s on %s: %s", attachment.Volume, attachment.Node, failure.message),
+			})
+		}
+	}
+
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].Namespace != issues[j].Namespace {
+			return issues[i].Namespace < issues[j].Namespace
+		}
+		if issues[i].Kind != issues[j].Kind {
+			return issues[i].Kind < issues[j].Kind
+		}
+		return issues[i].Name < issues[j].Name
+	})
+
+	return issues
+}
@@ -0,0 +1,359 @@
+package pulse
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/pager"
+)
+
+// tlsSecretSelector limits Secrets to the kubernetes.io/tls ones, the only
+// Secrets pulse reads.
+const tlsSecretSelector = "type=" + string(corev1.SecretTypeTLS)
+
+// listPageSize is the number of objects fetched per List call, so large
+// clusters are read in chunks instead of one unbounded response.
+const listPageSize = 500
+
+// Resource names used to key the informers a Client runs.
+const (
+	resourcePods           = "pods"
+	resourceNodes          = "nodes"
+	resourceDeployments    = "deployments"
+	resourceReplicaSets    = "replicasets"
+	resourceStatefulSets   = "statefulsets"
+	resourceDaemonSets     = "daemonsets"
+	resourceEvents         = "events"
+	resourceClaims         = "persistentvolumeclaims"
+	resourceVolumes        = "persistentvolumes"
+	resourceAttachments    = "volumeattachments"
+	resourceClasses        = "storageclasses"
+	resourceServices       = "services"
+	resourceEndpointSlices = "endpointslices"
+	resourceIngresses      = "ingresses"
+	resourceAutoscalers    = "horizontalpodautoscalers"
+	// resourceHTTPRoutes is the Gateway API HTTPRoute, a custom resource
+	// without an informer
+	resourceHTTPRoutes = "httproutes"
+	// resourceCertificates is the cert-manager Certificate, a custom
+	// resource without an informer
+	resourceCertificates = "certificates"
+	// resourceSecrets covers kubernetes.io/tls Secrets only, see
+	// tlsSecretInformer
+	resourceSecrets = "secrets"
+	// resourceCoreEvents is the core/v1 view of events, which has no
+	// informer and is only listed when events.k8s.io is unavailable
+	resourceCoreEvents = "events.core"
+)
+
+// informerCache holds the shared informers started by StartInformers. Every
+// getter reads from the same cache, so the pod, node and workload analyzers
+// never list a resource more than once.
+type informerCache struct {
+	informers map[string]cache.SharedIndexInformer
+	// forbidden records resources the caller may not list, or the server
+	// does not serve; their getters
+	// return the recorded error instead of waiting on an informer that can
+	// never sync
+	forbidden map[string]error
+	stop      chan struct{}
+	factory   informers.SharedInformerFactory
+}
+
+// StartInformers switches the client from paginated List calls to shared
+// informers, which keep a trimmed copy of each resource in memory and follow
+// changes with watches. Use it for long-running modes; one-shot runs are
+// cheaper with plain lists. namespace limits namespaced resources to it. ctx
+// bounds the initial sync only; the informers run until StopInformers.
+func (c *Client) StartInformers(ctx context.Context, namespace string) error {
+	factory := informers.NewSharedInformerFactoryWithOptions(c.clientset, 0,
+		informers.WithNamespace(namespace),
+		informers.WithTransform(trimObject))
+
+	resources := []struct {
+		name     string
+		probe    func(opts metav1.ListOptions) error
+		informer func() cache.SharedIndexInformer
+	}{
+		{
+			resourcePods,
+			func(opts metav1.ListOptions) error {
+				_, err := c.clientset.CoreV1().Pods(namespace).List(ctx, opts)
+				return err
+			},
+			factory.Core().V1().Pods().Informer,
+		},
+		{
+			resourceNodes,
+			func(opts metav1.ListOptions) error {
+				_, err := c.clientset.CoreV1().Nodes().List(ctx, opts)
+				return err
+			},
+			factory.Core().V1().Nodes().Informer,
+		},
+		{
+			resourceDeployments,
+			func(opts metav1.ListOptions) error {
+				_, err := c.clientset.AppsV1().Deployments(namespace).List(ctx, opts)
+				return err
+			},
+			factory.Apps().V1().Deployments().Informer,
+		},
+		{
+			resourceReplicaSets,
+			func(opts metav1.ListOptions) error {
+				_, err := c.clientset.AppsV1().ReplicaSets(namespace).List(ctx, opts)
+				return err
+			},
+			factory.Apps().V1().ReplicaSets().Informer,
+		},
+		{
+			resourceStatefulSets,
+			func(opts metav1.ListOptions) error {
+				_, err := c.clientset.AppsV1().StatefulSets(namespace).List(ctx, opts)
+				return err
+			},
+			factory.Apps().V1().StatefulSets().Informer,
+		},
+		{
+			resourceDaemonSets,
+			func(opts metav1.ListOptions) error {
+				_, err := c.clientset.AppsV1().DaemonSets(namespace).List(ctx, opts)
+				return err
+			},
+			factory.Apps().V1().DaemonSets().Informer,
+		},
+		{
+			resourceEvents,
+			func(opts metav1.ListOptions) error {
+				_, err := c.clientset.EventsV1().Events(namespace).List(ctx, opts)
+				return err
+			},
+			factory.Events().V1().Events().Informer,
+		},
+		{
+			resourceClaims,
+			func(opts metav1.ListOptions) error {
+				_, err := c.clientset.CoreV1().PersistentVolumeClaims(namespace).List(ctx, opts)
+				return err
+			},
+			factory.Core().V1().PersistentVolumeClaims().Informer,
+		},
+		{
+			resourceVolumes,
+			func(opts metav1.ListOptions) error {
+				_, err := c.clientset.CoreV1().PersistentVolumes().List(ctx, opts)
+				return err
+			},
+			factory.Core().V1().PersistentVolumes().Informer,
+		},
+		{
+			resourceAttachments,
+			func(opts metav1.ListOptions) error {
+				_, err := c.clientset.StorageV1().VolumeAttachments().List(ctx, opts)
+				return err
+			},
+			factory.Storage().V1().VolumeAttachments().Informer,
+		},
+		{
+			resourceClasses,
+			func(opts metav1.ListOptions) error {
+				_, err := c.clientset.StorageV1().StorageClasses().List(ctx, opts)
+				return err
+			},
+			factory.Storage().V1().StorageClasses().Informer,
+		},
+		{
+			resourceServices,
+			func(opts metav1.ListOptions) error {
+				_, err := c.clientset.CoreV1().Services(namespace).List(ctx, opts)
+				return err
+			},
+			factory.Core().V1().Services().Informer,
+		},
+		{
+			resourceEndpointSlices,
+			func(opts metav1.ListOptions) error {
+				_, err := c.clientset.DiscoveryV1().EndpointSlices(namespace).List(ctx, opts)
+				return err
+			},
+			factory.Discovery().V1().EndpointSlices().Informer,
+		},
+		{
+			resourceIngresses,
+			func(opts metav1.ListOptions) error {
+				_, err := c.clientset.NetworkingV1().Ingresses(namespace).List(ctx, opts)
+				return err
+			},
+			factory.Networking().V1().Ingresses().Informer,
+		},
+		{
+			resourceAutoscalers,
+			func(opts metav1.ListOptions) error {
+				_, err := c.clientset.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(ctx, opts)
+				return err
+			},
+			factory.Autoscaling().V2().HorizontalPodAutoscalers().Informer,
+		},
+		{
+			resourceSecrets,
+			func(opts metav1.ListOptions) error {
+				opts.FieldSelector = tlsSecretSelector
+				_, err := c.clientset.CoreV1().Secrets(namespace).List(ctx, opts)
+				return err
+			},
+			func() cache.SharedIndexInformer {
+				return factory.InformerFor(&corev1.Secret{}, func(clientset kubernetes.Interface, resync time.Duration) cache.SharedIndexInformer {
+					return coreinformers.NewFilteredSecretInformer(clientset, namespace, resync,
+						cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+						func(opts *metav1.ListOptions) { opts.FieldSelector = tlsSecretSelector })
+				})
+			},
+		},
+	}
+
+	ic := &informerCache{
+		informers: make(map[string]cache.SharedIndexInformer),
+		forbidden: make(map[string]error),
+		stop:      make(chan struct{}),
+		factory:   factory,
+	}
+
+	for _, resource := range resources {
+		// An informer for a resource we cannot list, or the server does not
+		// serve, would retry forever
+		if err := resource.probe(metav1.ListOptions{Limit: 1}); err != nil {
+			if apierrors.IsForbidden(err) || apierrors.IsNotFound(err) {
+				ic.forbidden[resource.name] = err
+				continue
+			}
+			return err
+		}
+		ic.informers[resource.name] = resource.informer()
+	}
+
+	factory.Start(ic.stop)
+	for informerType, synced := range factory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			close(ic.stop)
+			factory.Shutdown()
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			return fmt.Errorf("failed to sync informer cache for %v", informerType)
+		}
+	}
+
+	c.cache = ic
+	return nil
+}
+
+// StopInformers stops the informers and returns the client to List calls.
+func (c *Client) StopInformers() {
+	if c.cache == nil {
+		return
+	}
+	close(c.cache.stop)
+	c.cache.factory.Shutdown()
+	c.cache = nil
+}
+
+// each visits every object of a resource in namespace (or all namespaces
+// when empty), reading the informer cache when one is running for the
+// resource and paging through List calls otherwise. Objects are handed to fn
+// one at a time so callers can keep just the fields they need.
+func each[T runtime.Object](ctx context.Context, c *Client, resource string, namespace string, list pager.ListPageFunc, fn func(T)) error {
+	if c.cache != nil {
+		if err, ok := c.cache.forbidden[resource]; ok {
+			return err
+		}
+	}
+
+	if informer, ok := c.cache.informer(resource); ok {
+		var objects []any
+		if namespace == "" {
+			objects = informer.GetStore().List()
+		} else {
+			var err error
+			objects, err = informer.GetIndexer().ByIndex(cache.NamespaceIndex, namespace)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, object := range objects {
+			fn(object.(T))
+		}
+		return nil
+	}
+
+	p := pager.New(list)
+	p.PageSize = listPageSize
+	return p.EachListItem(ctx, metav1.ListOptions{}, func(object runtime.Object) error {
+		fn(object.(T))
+		return nil
+	})
+}
+
+// informer returns the running informer for resource, if any.
+func (ic *informerCache) informer(resource string) (cache.SharedIndexInformer, bool) {
+	if ic == nil {
+		return nil, false
+	}
+	informer, ok := ic.informers[resource]
+	return informer, ok
+}
+
+// trimObject drops the parts of cached objects pulse never reads, which for
+// pods are most of their bulk.
+func trimObject(object any) (any, error) {
+	if accessor, err := meta.Accessor(object); err == nil {
+		accessor.SetManagedFields(nil)
+
+		var annotations map[string]string
+		for _, key := range []string{revisionAnnotation, selectedNodeAnnotation} {
+			if value, ok := accessor.GetAnnotations()[key]; ok {
+				if annotations == nil {
+					annotations = make(map[string]string)
+				}
+				annotations[key] = value
+			}
+		}
+		accessor.SetAnnotations(annotations)
+	}
+
+	if pod, ok := object.(*corev1.Pod); ok {
+		trimContainers(pod.Spec.InitContainers)
+		trimContainers(pod.Spec.Containers)
+		pod.Spec.EphemeralContainers = nil
+	}
+
+	// Only the certificate is read; private keys are never kept
+	if secret, ok := object.(*corev1.Secret); ok {
+		secret.Data = map[string][]byte{corev1.TLSCertKey: secret.Data[corev1.TLSCertKey]}
+		secret.StringData = nil
+	}
+
+	return object, nil
+}
+
+// trimContainers keeps only the container name, image and resources.
+func trimContainers(containers []corev1.Container) {
+	for i, container := range containers {
+		containers[i] = corev1.Container{
+			Name:      container.Name,
+			Image:     container.Image,
+			Resources: container.Resources,
+		}
+	}
+}
@@ -2,19 +2,87 @@ package pulse
 
 import (
 	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
 	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
+// revisionAnnotation is set by the deployment controller on Deployments and
+// their ReplicaSets to track rollout revisions.
+const revisionAnnotation = "deployment.kubernetes.io/revision"
+
+// selectedNodeAnnotation is set on a WaitForFirstConsumer claim once the
+// scheduler has picked a node for a pod using it.
+const selectedNodeAnnotation = "volume.kubernetes.io/selected-node"
+
+// httpRouteResource is the Gateway API HTTPRoute, read through the dynamic
+// client as its CRDs may not be installed.
+var httpRouteResource = schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1", Resource: "httproutes"}
+
+// certificateResource is the cert-manager Certificate, read through the
+// dynamic client as cert-manager may not be installed.
+var certificateResource = schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+
 type Client struct {
 	clientset kubernetes.Interface
+	// dynamic reads custom resources such as Gateway API routes; nil skips
+	// them
+	dynamic dynamic.Interface
+	// host is the API server URL, used to key per-cluster local state
+	host string
+	// cache is set while informers are running, see StartInformers
+	cache *informerCache
+}
+
+// ClientOptions mirrors the kubectl global flags that decide which cluster,
+// context and identity the client talks to. Zero values fall back to the
+// standard kubeconfig loading rules ($KUBECONFIG, ~/.kube/config, in-cluster).
+type ClientOptions struct {
+	Kubeconfig     string
+	Context        string
+	Cluster        string
+	User           string
+	Impersonate    string
+	RequestTimeout string
 }
 
-func NewClient() (*Client, error) {
-	config, err := clientcmd.BuildConfigFromFlags("", clientcmd.RecommendedHomeFile)
+// RESTConfig resolves the options into a rest.Config the same way kubectl does.
+func (o ClientOptions) RESTConfig() (*rest.Config, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = o.Kubeconfig
+
+	overrides := &clientcmd.ConfigOverrides{
+		CurrentContext: o.Context,
+		Timeout:        o.RequestTimeout,
+	}
+	overrides.Context.Cluster = o.Cluster
+	overrides.Context.AuthInfo = o.User
+	overrides.AuthInfo.Impersonate = o.Impersonate
+
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+}
+
+func NewClient(opts ClientOptions) (*Client, error) {
+	config, err := opts.RESTConfig()
 	if err != nil {
 		return nil, err
 	}
@@ -24,23 +92,27 @@ func NewClient() (*Client, error) {
 		return nil, err
 	}
 
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Client{
 		clientset: clientset,
+		dynamic:   dynamicClient,
+		host:      config.Host,
 	}, nil
 }
 
-func (c *Client) GetPodStatuses() ([]PodStatus, error) {
-	return c.GetPodStatusesInNamespace("")
+func (c *Client) GetPodStatuses(ctx context.Context) ([]PodStatus, error) {
+	return c.GetPodStatusesInNamespace(ctx, "")
 }
 
-func (c *Client) GetPodStatusesInNamespace(namespace string) ([]PodStatus, error) {
-	pods, err := c.clientset.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{})
-	if err != nil {
-		return nil, err
-	}
-
+func (c *Client) GetPodStatusesInNamespace(ctx context.Context, namespace string) ([]PodStatus, error) {
 	var podStatuses []PodStatus
-	for _, pod := range pods.Items {
+	err := each(ctx, c, resourcePods, namespace, func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return c.clientset.CoreV1().Pods(namespace).List(ctx, opts)
+	}, func(pod *corev1.Pod) {
 		var restarts int32
 		var lastRestart time.Time
 
@@ -54,13 +126,979 @@ func (c *Client) GetPodStatusesInNamespace(namespace string) ([]PodStatus, error
 		}
 
 		podStatuses = append(podStatuses, PodStatus{
+			UID:         string(pod.UID),
 			Name:        pod.Name,
 			Namespace:   pod.Namespace,
 			Status:      string(pod.Status.Phase),
+			Created:     pod.CreationTimestamp.Time,
 			Restarts:    restarts,
 			LastRestart: lastRestart,
+			Containers:  containerStatuses(pod),
+			Reasons:     containerReasons(pod),
+			OOMKills:    oomKills(pod),
+			Pending:     pendingDiagnosis(pod),
+			Labels:      pod.Labels,
 		})
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return podStatuses, nil
 }
+
+// containerStatuses returns per-container restart details for the init, app
+// and ephemeral containers of the pod, in that order.
+func containerStatuses(pod *corev1.Pod) []ContainerStatus {
+	var containers []ContainerStatus
+
+	add := func(statuses []corev1.ContainerStatus, init, ephemeral bool) {
+		for _, status := range statuses {
+			container := ContainerStatus{
+				Name:      status.Name,
+				Image:     status.Image,
+				Init:      init,
+				Ephemeral: ephemeral,
+				Restarts:  status.RestartCount,
+			}
+			if terminated := status.LastTerminationState.Terminated; terminated != nil {
+				container.LastTermination = terminated.FinishedAt.Time
+				container.ExitCode = terminated.ExitCode
+				container.Reason = terminated.Reason
+			}
+			containers = append(containers, container)
+		}
+	}
+
+	add(pod.Status.InitContainerStatuses, true, false)
+	add(pod.Status.ContainerStatuses, false, false)
+	add(pod.Status.EphemeralContainerStatuses, false, true)
+
+	return containers
+}
+
+// containerReasons collects the current waiting or terminated reason and the
+// last termination reason of every init and app container in the pod.
+func containerReasons(pod *corev1.Pod) []ContainerReason {
+	var reasons []ContainerReason
+
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if waiting := status.State.Waiting; waiting != nil && waiting.Reason != "" {
+			reasons = append(reasons, ContainerReason{
+				Container: status.Name,
+				Reason:    waiting.Reason,
+			})
+		}
+		if terminated := status.State.Terminated; terminated != nil && terminated.Reason != "" {
+			reasons = append(reasons, ContainerReason{
+				Container:  status.Name,
+				Reason:     terminated.Reason,
+				FinishedAt: terminated.FinishedAt.Time,
+			})
+		}
+		if terminated := status.LastTerminationState.Terminated; terminated != nil && terminated.Reason != "" {
+			reasons = append(reasons, ContainerReason{
+				Container:  status.Name,
+				Reason:     terminated.Reason,
+				FinishedAt: terminated.FinishedAt.Time,
+			})
+		}
+	}
+
+	return reasons
+}
+
+// pendingDiagnosis explains why a Pending pod has not started: the scheduler
+// has not placed it, or the kubelet is still pulling images, running init
+// containers or creating containers. It returns nil for pods in other phases.
+func pendingDiagnosis(pod *corev1.Pod) *PendingPod {
+	if pod.Status.Phase != corev1.PodPending {
+		return nil
+	}
+
+	pending := &PendingPod{
+		Namespace: pod.Namespace,
+		Pod:       pod.Name,
+		Cause:     PendingAwaitingScheduler,
+		Since:     pod.CreationTimestamp.Time,
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type != corev1.PodScheduled {
+			continue
+		}
+		if condition.Status == corev1.ConditionTrue {
+			pending.Cause = PendingContainerCreating
+			break
+		}
+
+		pending.Message = condition.Message
+		message := strings.ToLower(condition.Message)
+		switch {
+		case condition.Reason == corev1.PodReasonSchedulingGated:
+			pending.Cause = PendingSchedulingGated
+		case strings.Contains(message, "persistentvolumeclaim") || strings.Contains(message, "persistent volume"):
+			pending.Cause = PendingVolumeBinding
+		default:
+			pending.Cause = PendingUnschedulable
+		}
+		return pending
+	}
+	if pending.Cause == PendingAwaitingScheduler {
+		return pending
+	}
+
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if waiting := status.State.Waiting; waiting != nil && (waiting.Reason == "ErrImagePull" || waiting.Reason == "ImagePullBackOff") {
+			pending.Cause = PendingImagePull
+			pending.Message = fmt.Sprintf("%s: %s", status.Name, waiting.Reason)
+			if waiting.Message != "" {
+				pending.Message += " - " + waiting.Message
+			}
+			return pending
+		}
+	}
+
+	for _, status := range pod.Status.InitContainerStatuses {
+		if terminated := status.State.Terminated; terminated != nil && terminated.ExitCode == 0 {
+			continue
+		}
+
+		pending.Cause = PendingInitContainers
+		switch {
+		case status.State.Running != nil:
+			pending.Message = fmt.Sprintf("%s: running since %s", status.Name, status.State.Running.StartedAt.UTC().Format(time.RFC3339))
+		case status.State.Waiting != nil && status.State.Waiting.Reason != "":
+			pending.Message = fmt.Sprintf("%s: %s", status.Name, status.State.Waiting.Reason)
+		case status.State.Terminated != nil:
+			pending.Message = fmt.Sprintf("%s: exited %d %s", status.Name, status.State.Terminated.ExitCode, status.State.Terminated.Reason)
+		default:
+			pending.Message = status.Name + ": not started"
+		}
+		if status.RestartCount > 0 {
+			pending.Message += fmt.Sprintf(" (%d restarts)", status.RestartCount)
+		}
+		return pending
+	}
+
+	for _, status := range pod.Status.ContainerStatuses {
+		if waiting := status.State.Waiting; waiting != nil && waiting.Message != "" {
+			pending.Message = fmt.Sprintf("%s: %s - %s", status.Name, waiting.Reason, waiting.Message)
+			break
+		}
+	}
+	return pending
+}
+
+// oomKills returns the containers whose current or last termination was an
+// OOM kill, together with their memory request and limit.
+func oomKills(pod *corev1.Pod) []OOMKill {
+	resources := make(map[string]corev1.ResourceRequirements)
+	for _, container := range append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...) {
+		resources[container.Name] = container.Resources
+	}
+
+	var kills []OOMKill
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		terminated := status.State.Terminated
+		if terminated == nil {
+			terminated = status.LastTerminationState.Terminated
+		}
+		if terminated == nil || terminated.Reason != "OOMKilled" {
+			continue
+		}
+
+		kill := OOMKill{
+			Namespace: pod.Namespace,
+			Pod:       pod.Name,
+			Container: status.Name,
+			Restarts:  status.RestartCount,
+			KilledAt:  terminated.FinishedAt.Time,
+		}
+		if request, ok := resources[status.Name].Requests[corev1.ResourceMemory]; ok {
+			kill.MemoryRequest = request.String()
+		}
+		if limit, ok := resources[status.Name].Limits[corev1.ResourceMemory]; ok {
+			kill.MemoryLimit = limit.String()
+		}
+
+		kills = append(kills, kill)
+	}
+
+	return kills
+}
+
+func (c *Client) GetNodeStatuses(ctx context.Context) ([]NodeStatus, error) {
+	var nodeStatuses []NodeStatus
+	err := each(ctx, c, resourceNodes, "", func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return c.clientset.CoreV1().Nodes().List(ctx, opts)
+	}, func(node *corev1.Node) {
+		status := NodeStatus{
+			Name:          node.Name,
+			Ready:         string(corev1.ConditionUnknown),
+			Unschedulable: node.Spec.Unschedulable,
+		}
+
+		for _, condition := range node.Status.Conditions {
+			switch condition.Type {
+			case corev1.NodeReady:
+				status.Ready = string(condition.Status)
+			case corev1.NodeMemoryPressure, corev1.NodeDiskPressure, corev1.NodePIDPressure, corev1.NodeNetworkUnavailable:
+				if condition.Status == corev1.ConditionTrue {
+					status.Pressure = append(status.Pressure, string(condition.Type))
+				}
+			}
+		}
+
+		nodeStatuses = append(nodeStatuses, status)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nodeStatuses, nil
+}
+
+// GetDeployments returns the Deployments in namespace, or in all namespaces
+// when it is empty. Workloads and rollouts are both derived from them, so
+// they are read once and passed to GetWorkloadStatuses and
+// GetRolloutStatuses.
+func (c *Client) GetDeployments(ctx context.Context, namespace string) ([]*appsv1.Deployment, error) {
+	var deployments []*appsv1.Deployment
+	err := each(ctx, c, resourceDeployments, namespace, func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return c.clientset.AppsV1().Deployments(namespace).List(ctx, opts)
+	}, func(deployment *appsv1.Deployment) {
+		deployments = append(deployments, deployment)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deployments, nil
+}
+
+// GetWorkloadStatuses returns replica counts for the given Deployments and
+// the StatefulSets and DaemonSets in namespace, or in all namespaces when it
+// is empty.
+func (c *Client) GetWorkloadStatuses(ctx context.Context, namespace string, deployments []*appsv1.Deployment) ([]WorkloadStatus, error) {
+	var workloads []WorkloadStatus
+
+	for _, deployment := range deployments {
+		desired := int32(1)
+		if deployment.Spec.Replicas != nil {
+			desired = *deployment.Spec.Replicas
+		}
+		workloads = append(workloads, WorkloadStatus{
+			Kind:      "Deployment",
+			Name:      deployment.Name,
+			Namespace: deployment.Namespace,
+			Desired:   desired,
+			Ready:     deployment.Status.ReadyReplicas,
+			Available: deployment.Status.AvailableReplicas,
+		})
+	}
+
+	err := each(ctx, c, resourceStatefulSets, namespace, func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return c.clientset.AppsV1().StatefulSets(namespace).List(ctx, opts)
+	}, func(statefulSet *appsv1.StatefulSet) {
+		desired := int32(1)
+		if statefulSet.Spec.Replicas != nil {
+			desired = *statefulSet.Spec.Replicas
+		}
+		workloads = append(workloads, WorkloadStatus{
+			Kind:      "StatefulSet",
+			Name:      statefulSet.Name,
+			Namespace: statefulSet.Namespace,
+			Desired:   desired,
+			Ready:     statefulSet.Status.ReadyReplicas,
+			Available: statefulSet.Status.AvailableReplicas,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = each(ctx, c, resourceDaemonSets, namespace, func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return c.clientset.AppsV1().DaemonSets(namespace).List(ctx, opts)
+	}, func(daemonSet *appsv1.DaemonSet) {
+		workloads = append(workloads, WorkloadStatus{
+			Kind:      "DaemonSet",
+			Name:      daemonSet.Name,
+			Namespace: daemonSet.Namespace,
+			Desired:   daemonSet.Status.DesiredNumberScheduled,
+			Ready:     daemonSet.Status.NumberReady,
+			Available: daemonSet.Status.NumberAvailable,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return workloads, nil
+}
+
+// GetRolloutStatuses pairs each of the given Deployments with the ReplicaSet
+// in namespace that carries its current revision.
+func (c *Client) GetRolloutStatuses(ctx context.Context, namespace string, deployments []*appsv1.Deployment) ([]RolloutStatus, error) {
+	// Index the fields needed from ReplicaSets by owning deployment and revision
+	type revisionKey struct {
+		namespace, deployment, revision string
+	}
+	type replicaSetSummary struct {
+		name      string
+		created   time.Time
+		desired   int32
+		available int32
+	}
+	replicaSetsByRevision := make(map[revisionKey]replicaSetSummary)
+
+	err := each(ctx, c, resourceReplicaSets, namespace, func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return c.clientset.AppsV1().ReplicaSets(namespace).List(ctx, opts)
+	}, func(replicaSet *appsv1.ReplicaSet) {
+		owner := metav1.GetControllerOf(replicaSet)
+		if owner == nil || owner.Kind != "Deployment" {
+			return
+		}
+		summary := replicaSetSummary{
+			name:      replicaSet.Name,
+			created:   replicaSet.CreationTimestamp.Time,
+			available: replicaSet.Status.AvailableReplicas,
+		}
+		if replicaSet.Spec.Replicas != nil {
+			summary.desired = *replicaSet.Spec.Replicas
+		}
+		replicaSetsByRevision[revisionKey{replicaSet.Namespace, owner.Name, replicaSet.Annotations[revisionAnnotation]}] = summary
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var rollouts []RolloutStatus
+	for _, deployment := range deployments {
+		rollout := RolloutStatus{
+			Name:               deployment.Name,
+			Namespace:          deployment.Namespace,
+			Generation:         deployment.Generation,
+			ObservedGeneration: deployment.Status.ObservedGeneration,
+			Revision:           deployment.Annotations[revisionAnnotation],
+		}
+
+		for _, condition := range deployment.Status.Conditions {
+			if condition.Type == appsv1.DeploymentProgressing {
+				rollout.ProgressReason = condition.Reason
+				rollout.ProgressMessage = condition.Message
+			}
+		}
+
+		if replicaSet, ok := replicaSetsByRevision[revisionKey{deployment.Namespace, deployment.Name, rollout.Revision}]; ok && rollout.Revision != "" {
+			rollout.ReplicaSet = replicaSet.name
+			rollout.ReplicaSetCreated = replicaSet.created
+			rollout.ReplicaSetDesired = replicaSet.desired
+			rollout.ReplicaSetAvailable = replicaSet.available
+		}
+
+		rollouts = append(rollouts, rollout)
+	}
+
+	return rollouts, nil
+}
+
+// GetEvents returns the Events in namespace, or in all namespaces when it is
+// empty. core/v1 and events.k8s.io/v1 are two views of the same objects, so
+// reading both would count every event twice: events.k8s.io is read when the
+// server serves it and the caller may list it, and core/v1 otherwise.
+func (c *Client) GetEvents(ctx context.Context, namespace string) ([]Event, error) {
+	var events []Event
+	err := each(ctx, c, resourceEvents, namespace, func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return c.clientset.EventsV1().Events(namespace).List(ctx, opts)
+	}, func(event *eventsv1.Event) {
+		count := int32(1)
+		lastSeen := latest(event.CreationTimestamp.Time, event.EventTime.Time, event.DeprecatedLastTimestamp.Time)
+		if event.Series != nil {
+			count = event.Series.Count
+			lastSeen = latest(lastSeen, event.Series.LastObservedTime.Time)
+		} else if event.DeprecatedCount > 0 {
+			count = event.DeprecatedCount
+		}
+
+		events = append(events, Event{
+			Type:      event.Type,
+			Reason:    event.Reason,
+			Message:   event.Note,
+			Namespace: event.Regarding.Namespace,
+			Kind:      event.Regarding.Kind,
+			Name:      event.Regarding.Name,
+			Count:     count,
+			LastSeen:  lastSeen,
+		})
+	})
+	if apierrors.IsNotFound(err) || apierrors.IsForbidden(err) {
+		events = nil
+		err = each(ctx, c, resourceCoreEvents, namespace, func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return c.clientset.CoreV1().Events(namespace).List(ctx, opts)
+		}, func(event *corev1.Event) {
+			count := max(event.Count, 1)
+			lastSeen := latest(event.CreationTimestamp.Time, event.EventTime.Time, event.LastTimestamp.Time)
+			if event.Series != nil {
+				count = event.Series.Count
+				lastSeen = latest(lastSeen, event.Series.LastObservedTime.Time)
+			}
+
+			events = append(events, Event{
+				Type:      event.Type,
+				Reason:    event.Reason,
+				Message:   event.Message,
+				Namespace: event.InvolvedObject.Namespace,
+				Kind:      event.InvolvedObject.Kind,
+				Name:      event.InvolvedObject.Name,
+				Count:     count,
+				LastSeen:  lastSeen,
+			})
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// GetStorageStatus returns the PersistentVolumeClaims in namespace, or in all
+// namespaces when it is empty, together with the cluster's PersistentVolumes
+// and VolumeAttachments. The cluster-scoped objects are left out when the
+// caller may not list them.
+func (c *Client) GetStorageStatus(ctx context.Context, namespace string) (StorageStatus, error) {
+	var storage StorageStatus
+
+	// Without storage classes no claim is known to wait for its consumer
+	waitForConsumer := make(map[string]bool)
+	err := each(ctx, c, resourceClasses, "", func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return c.clientset.StorageV1().StorageClasses().List(ctx, opts)
+	}, func(class *storagev1.StorageClass) {
+		if class.VolumeBindingMode != nil && *class.VolumeBindingMode == storagev1.VolumeBindingWaitForFirstConsumer {
+			waitForConsumer[class.Name] = true
+		}
+	})
+	if err != nil && !apierrors.IsForbidden(err) {
+		return StorageStatus{}, err
+	}
+
+	err = each(ctx, c, resourceClaims, namespace, func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return c.clientset.CoreV1().PersistentVolumeClaims(namespace).List(ctx, opts)
+	}, func(claim *corev1.PersistentVolumeClaim) {
+		status := VolumeClaimStatus{
+			Namespace: claim.Namespace,
+			Name:      claim.Name,
+			Phase:     string(claim.Status.Phase),
+			Created:   claim.CreationTimestamp.Time,
+		}
+		if claim.Spec.StorageClassName != nil && waitForConsumer[*claim.Spec.StorageClassName] {
+			_, selected := claim.Annotations[selectedNodeAnnotation]
+			status.WaitingForConsumer = !selected
+		}
+		storage.Claims = append(storage.Claims, status)
+	})
+	if err != nil {
+		return StorageStatus{}, err
+	}
+
+	err = each(ctx, c, resourceVolumes, "", func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return c.clientset.CoreV1().PersistentVolumes().List(ctx, opts)
+	}, func(volume *corev1.PersistentVolume) {
+		status := VolumeStatus{
+			Name:          volume.Name,
+			Phase:         string(volume.Status.Phase),
+			Message:       volume.Status.Message,
+			ReclaimPolicy: string(volume.Spec.PersistentVolumeReclaimPolicy),
+		}
+		if claim := volume.Spec.ClaimRef; claim != nil {
+			status.ClaimNamespace = claim.Namespace
+			status.Claim = claim.Name
+		}
+		storage.Volumes = append(storage.Volumes, status)
+	})
+	if err != nil && !apierrors.IsForbidden(err) {
+		return StorageStatus{}, err
+	}
+
+	err = each(ctx, c, resourceAttachments, "", func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return c.clientset.StorageV1().VolumeAttachments().List(ctx, opts)
+	}, func(attachment *storagev1.VolumeAttachment) {
+		status := VolumeAttachmentStatus{
+			Name: attachment.Name,
+			Node: attachment.Spec.NodeName,
+		}
+		if attachment.Spec.Source.PersistentVolumeName != nil {
+			status.Volume = *attachment.Spec.Source.PersistentVolumeName
+		}
+		if attachError := attachment.Status.AttachError; attachError != nil {
+			status.AttachError = attachError.Message
+		}
+		if detachError := attachment.Status.DetachError; detachError != nil {
+			status.DetachError = detachError.Message
+		}
+		storage.Attachments = append(storage.Attachments, status)
+	})
+	if err != nil && !apierrors.IsForbidden(err) {
+		return StorageStatus{}, err
+	}
+
+	return storage, nil
+}
+
+// GetServiceStatuses returns the Services in namespace, or in all namespaces
+// when it is empty, with their endpoints counted from EndpointSlices. Pods is
+// left for the analyzer to fill in.
+func (c *Client) GetServiceStatuses(ctx context.Context, namespace string) ([]ServiceStatus, error) {
+	type serviceKey struct {
+		namespace, name string
+	}
+	type endpointCounts struct {
+		// Dual-stack services have a slice per address family listing the
+		// same endpoints, so endpoints are counted once per family
+		ready, total map[discoveryv1.AddressType]int
+	}
+	counts := make(map[serviceKey]*endpointCounts)
+
+	err := each(ctx, c, resourceEndpointSlices, namespace, func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return c.clientset.DiscoveryV1().EndpointSlices(namespace).List(ctx, opts)
+	}, func(slice *discoveryv1.EndpointSlice) {
+		service, ok := slice.Labels[discoveryv1.LabelServiceName]
+		if !ok {
+			return
+		}
+		key := serviceKey{slice.Namespace, service}
+		if counts[key] == nil {
+			counts[key] = &endpointCounts{
+				ready: make(map[discoveryv1.AddressType]int),
+				total: make(map[discoveryv1.AddressType]int),
+			}
+		}
+
+		for _, endpoint := range slice.Endpoints {
+			if endpoint.Conditions.Terminating != nil && *endpoint.Conditions.Terminating {
+				continue
+			}
+			counts[key].total[slice.AddressType]++
+			// A missing Ready condition is to be read as ready
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				counts[key].ready[slice.AddressType]++
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var services []ServiceStatus
+	err = each(ctx, c, resourceServices, namespace, func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return c.clientset.CoreV1().Services(namespace).List(ctx, opts)
+	}, func(service *corev1.Service) {
+		status := ServiceStatus{
+			Namespace: service.Namespace,
+			Name:      service.Name,
+			Type:      string(service.Spec.Type),
+			Selector:  service.Spec.Selector,
+		}
+		for _, port := range service.Spec.Ports {
+			status.Ports = append(status.Ports, ServicePort{Name: port.Name, Port: port.Port})
+		}
+		if endpoints := counts[serviceKey{service.Namespace, service.Name}]; endpoints != nil {
+			for family, total := range endpoints.total {
+				if total > status.Endpoints || (total == status.Endpoints && endpoints.ready[family] > status.ReadyEndpoints) {
+					status.Endpoints = total
+					status.ReadyEndpoints = endpoints.ready[family]
+				}
+			}
+		}
+		services = append(services, status)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return services, nil
+}
+
+// GetAutoscalerStatuses returns the HorizontalPodAutoscalers in namespace, or
+// in all namespaces when it is empty.
+func (c *Client) GetAutoscalerStatuses(ctx context.Context, namespace string) ([]AutoscalerStatus, error) {
+	var autoscalers []AutoscalerStatus
+	err := each(ctx, c, resourceAutoscalers, namespace, func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return c.clientset.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(ctx, opts)
+	}, func(hpa *autoscalingv2.HorizontalPodAutoscaler) {
+		status := AutoscalerStatus{
+			Namespace:       hpa.Namespace,
+			Name:            hpa.Name,
+			Target:          hpa.Spec.ScaleTargetRef.Kind + "/" + hpa.Spec.ScaleTargetRef.Name,
+			MinReplicas:     1,
+			MaxReplicas:     hpa.Spec.MaxReplicas,
+			CurrentReplicas: hpa.Status.CurrentReplicas,
+			DesiredReplicas: hpa.Status.DesiredReplicas,
+		}
+		if hpa.Spec.MinReplicas != nil {
+			status.MinReplicas = *hpa.Spec.MinReplicas
+		}
+
+		current := make(map[string]string)
+		for _, metric := range hpa.Status.CurrentMetrics {
+			name, value := metricValue(metric)
+			current[name] = value
+		}
+		for _, metric := range hpa.Spec.Metrics {
+			name, target := metricTarget(metric)
+			value, ok := current[name]
+			if !ok {
+				value = "unknown"
+			}
+			status.Metrics = append(status.Metrics, AutoscalerMetric{
+				Name:    name,
+				Current: value,
+				Target:  target,
+			})
+		}
+
+		for _, condition := range hpa.Status.Conditions {
+			switch condition.Type {
+			case autoscalingv2.ScalingActive:
+				status.ScalingActive = string(condition.Status)
+				status.ScalingActiveReason = condition.Reason
+				status.ScalingActiveMessage = condition.Message
+			case autoscalingv2.ScalingLimited:
+				status.ScalingLimited = string(condition.Status)
+				status.ScalingLimitedReason = condition.Reason
+				status.ScalingLimitedMessage = condition.Message
+			}
+		}
+
+		autoscalers = append(autoscalers, status)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return autoscalers, nil
+}
+
+// metricName names the metric an autoscaler scales on from the fields its
+// spec and status share: the resource for resource metrics, prefixed with the
+// container for container metrics, and the metric name otherwise. Naming both
+// the same way lets current values be paired with their targets.
+func metricName(metricType autoscalingv2.MetricSourceType, resource corev1.ResourceName, container string, metric string) string {
+	switch metricType {
+	case autoscalingv2.ResourceMetricSourceType:
+		return string(resource)
+	case autoscalingv2.ContainerResourceMetricSourceType:
+		return container + "/" + string(resource)
+	case autoscalingv2.PodsMetricSourceType, autoscalingv2.ObjectMetricSourceType, autoscalingv2.ExternalMetricSourceType:
+		return metric
+	default:
+		return string(metricType)
+	}
+}
+
+// metricTarget names a metric an autoscaler scales on and renders its target
+// as a utilization percentage or a quantity.
+func metricTarget(metric autoscalingv2.MetricSpec) (string, string) {
+	var name string
+	var target autoscalingv2.MetricTarget
+	switch {
+	case metric.Resource != nil:
+		name, target = metricName(metric.Type, metric.Resource.Name, "", ""), metric.Resource.Target
+	case metric.ContainerResource != nil:
+		name, target = metricName(metric.Type, metric.ContainerResource.Name, metric.ContainerResource.Container, ""), metric.ContainerResource.Target
+	case metric.Pods != nil:
+		name, target = metricName(metric.Type, "", "", metric.Pods.Metric.Name), metric.Pods.Target
+	case metric.Object != nil:
+		name, target = metricName(metric.Type, "", "", metric.Object.Metric.Name), metric.Object.Target
+	case metric.External != nil:
+		name, target = metricName(metric.Type, "", "", metric.External.Metric.Name), metric.External.Target
+	default:
+		name = string(metric.Type)
+	}
+
+	switch {
+	case target.AverageUtilization != nil:
+		return name, fmt.Sprintf("%d%%", *target.AverageUtilization)
+	case target.AverageValue != nil:
+		return name, target.AverageValue.String()
+	case target.Value != nil:
+		return name, target.Value.String()
+	default:
+		return name, ""
+	}
+}
+
+// metricValue names a current metric like metricTarget names its spec and
+// renders its value like metricTarget renders targets, or "unknown" when the
+// autoscaler has no value for it.
+func metricValue(metric autoscalingv2.MetricStatus) (string, string) {
+	var name string
+	var value autoscalingv2.MetricValueStatus
+	switch {
+	case metric.Resource != nil:
+		name, value = metricName(metric.Type, metric.Resource.Name, "", ""), metric.Resource.Current
+	case metric.ContainerResource != nil:
+		name, value = metricName(metric.Type, metric.ContainerResource.Name, metric.ContainerResource.Container, ""), metric.ContainerResource.Current
+	case metric.Pods != nil:
+		name, value = metricName(metric.Type, "", "", metric.Pods.Metric.Name), metric.Pods.Current
+	case metric.Object != nil:
+		name, value = metricName(metric.Type, "", "", metric.Object.Metric.Name), metric.Object.Current
+	case metric.External != nil:
+		name, value = metricName(metric.Type, "", "", metric.External.Metric.Name), metric.External.Current
+	default:
+		name = string(metric.Type)
+	}
+
+	switch {
+	case value.AverageUtilization != nil:
+		return name, fmt.Sprintf("%d%%", *value.AverageUtilization)
+	case value.AverageValue != nil:
+		return name, value.AverageValue.String()
+	case value.Value != nil:
+		return name, value.Value.String()
+	default:
+		return name, "unknown"
+	}
+}
+
+// GetRouteStatuses returns the Ingresses and Gateway API HTTPRoutes in
+// namespace, or in all namespaces when it is empty. HTTPRoutes are left out
+// when the Gateway API is not installed or the caller may not list them.
+func (c *Client) GetRouteStatuses(ctx context.Context, namespace string) ([]RouteStatus, error) {
+	var routes []RouteStatus
+	err := each(ctx, c, resourceIngresses, namespace, func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return c.clientset.NetworkingV1().Ingresses(namespace).List(ctx, opts)
+	}, func(ingress *networkingv1.Ingress) {
+		route := RouteStatus{
+			Kind:      "Ingress",
+			Namespace: ingress.Namespace,
+			Name:      ingress.Name,
+		}
+		addBackend := func(backend *networkingv1.IngressBackend) {
+			// Resource backends point at custom objects, not Services
+			if backend == nil || backend.Service == nil {
+				return
+			}
+			route.Backends = append(route.Backends, RouteBackend{
+				Namespace: ingress.Namespace,
+				Service:   backend.Service.Name,
+				Port:      backend.Service.Port.Number,
+				PortName:  backend.Service.Port.Name,
+			})
+		}
+
+		addBackend(ingress.Spec.DefaultBackend)
+		for _, rule := range ingress.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+			for _, path := range rule.HTTP.Paths {
+				addBackend(&path.Backend)
+			}
+		}
+		routes = append(routes, route)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if c.dynamic == nil {
+		return routes, nil
+	}
+
+	err = each(ctx, c, resourceHTTPRoutes, namespace, func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return c.dynamic.Resource(httpRouteResource).Namespace(namespace).List(ctx, opts)
+	}, func(object *unstructured.Unstructured) {
+		routes = append(routes, httpRouteStatus(object))
+	})
+	if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsForbidden(err) {
+		return nil, err
+	}
+
+	return routes, nil
+}
+
+// httpRouteStatus reads the Service backends of an HTTPRoute and the
+// conditions its parent Gateways reported on it.
+func httpRouteStatus(object *unstructured.Unstructured) RouteStatus {
+	route := RouteStatus{
+		Kind:      "HTTPRoute",
+		Namespace: object.GetNamespace(),
+		Name:      object.GetName(),
+	}
+
+	rules, _, _ := unstructured.NestedSlice(object.Object, "spec", "rules")
+	for _, rule := range rules {
+		rule, ok := rule.(map[string]any)
+		if !ok {
+			continue
+		}
+		backendRefs, _, _ := unstructured.NestedSlice(rule, "backendRefs")
+		for _, ref := range backendRefs {
+			ref, ok := ref.(map[string]any)
+			if !ok {
+				continue
+			}
+			group, _, _ := unstructured.NestedString(ref, "group")
+			kind, _, _ := unstructured.NestedString(ref, "kind")
+			if group != "" || (kind != "" && kind != "Service") {
+				continue
+			}
+
+			backend := RouteBackend{Namespace: route.Namespace}
+			backend.Service, _, _ = unstructured.NestedString(ref, "name")
+			if namespace, _, _ := unstructured.NestedString(ref, "namespace"); namespace != "" {
+				backend.Namespace = namespace
+			}
+			if port, ok, _ := unstructured.NestedInt64(ref, "port"); ok {
+				backend.Port = int32(port)
+			}
+			route.Backends = append(route.Backends, backend)
+		}
+	}
+
+	parents, _, _ := unstructured.NestedSlice(object.Object, "status", "parents")
+	for _, parent := range parents {
+		parent, ok := parent.(map[string]any)
+		if !ok {
+			continue
+		}
+		parentName, _, _ := unstructured.NestedString(parent, "parentRef", "name")
+		conditions, _, _ := unstructured.NestedSlice(parent, "conditions")
+		for _, condition := range conditions {
+			condition, ok := condition.(map[string]any)
+			if !ok {
+				continue
+			}
+			status := RouteCondition{Parent: parentName}
+			status.Type, _, _ = unstructured.NestedString(condition, "type")
+			status.Status, _, _ = unstructured.NestedString(condition, "status")
+			status.Reason, _, _ = unstructured.NestedString(condition, "reason")
+			status.Message, _, _ = unstructured.NestedString(condition, "message")
+			route.Conditions = append(route.Conditions, status)
+		}
+	}
+
+	return route
+}
+
+// GetCertificates returns the certificates of the kubernetes.io/tls Secrets
+// and cert-manager Certificates in namespace, or in all namespaces when it is
+// empty. Either source is left out when the caller may not list it, and
+// Certificates when cert-manager is not installed; only when both are
+// forbidden is the error returned.
+func (c *Client) GetCertificates(ctx context.Context, namespace string) (CertificateInventory, error) {
+	var inventory CertificateInventory
+	secretsErr := each(ctx, c, resourceSecrets, namespace, func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		opts.FieldSelector = tlsSecretSelector
+		return c.clientset.CoreV1().Secrets(namespace).List(ctx, opts)
+	}, func(secret *corev1.Secret) {
+		if secret.Type != corev1.SecretTypeTLS {
+			return
+		}
+		inventory.Certificates = append(inventory.Certificates, secretCertificate(secret))
+	})
+	if secretsErr != nil && !apierrors.IsForbidden(secretsErr) {
+		return CertificateInventory{}, secretsErr
+	}
+	inventory.SecretsForbidden = secretsErr != nil
+
+	if c.dynamic == nil {
+		return inventory, secretsErr
+	}
+
+	err := each(ctx, c, resourceCertificates, namespace, func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return c.dynamic.Resource(certificateResource).Namespace(namespace).List(ctx, opts)
+	}, func(object *unstructured.Unstructured) {
+		inventory.Certificates = append(inventory.Certificates, certManagerCertificate(object))
+	})
+	switch {
+	case apierrors.IsNotFound(err):
+		return inventory, secretsErr
+	case apierrors.IsForbidden(err) && secretsErr != nil:
+		return CertificateInventory{}, secretsErr
+	case err != nil && !apierrors.IsForbidden(err):
+		return CertificateInventory{}, err
+	}
+
+	return inventory, nil
+}
+
+// secretCertificate reads the leaf certificate, the first in the chain, of a
+// kubernetes.io/tls Secret.
+func secretCertificate(secret *corev1.Secret) CertificateStatus {
+	status := CertificateStatus{
+		Kind:      "Secret",
+		Namespace: secret.Namespace,
+		Name:      secret.Name,
+	}
+
+	block, _ := pem.Decode(secret.Data[corev1.TLSCertKey])
+	if block == nil {
+		status.Error = "no PEM certificate in " + corev1.TLSCertKey
+		return status
+	}
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+
+	status.NotAfter = certificate.NotAfter
+	status.Subject = certificate.Subject.CommonName
+	if status.Subject == "" && len(certificate.DNSNames) > 0 {
+		status.Subject = certificate.DNSNames[0]
+	}
+	return status
+}
+
+// certManagerCertificate reads the expiry and Ready condition of a
+// cert-manager Certificate.
+func certManagerCertificate(object *unstructured.Unstructured) CertificateStatus {
+	status := CertificateStatus{
+		Kind:      "Certificate",
+		Namespace: object.GetNamespace(),
+		Name:      object.GetName(),
+	}
+	status.SecretName, _, _ = unstructured.NestedString(object.Object, "spec", "secretName")
+	status.Subject, _, _ = unstructured.NestedString(object.Object, "spec", "commonName")
+	if status.Subject == "" {
+		if dnsNames, _, _ := unstructured.NestedStringSlice(object.Object, "spec", "dnsNames"); len(dnsNames) > 0 {
+			status.Subject = dnsNames[0]
+		}
+	}
+	if notAfter, _, _ := unstructured.NestedString(object.Object, "status", "notAfter"); notAfter != "" {
+		status.NotAfter, _ = time.Parse(time.RFC3339, notAfter)
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(object.Object, "status", "conditions")
+	for _, condition := range conditions {
+		condition, ok := condition.(map[string]any)
+		if !ok {
+			continue
+		}
+		if conditionType, _, _ := unstructured.NestedString(condition, "type"); conditionType != "Ready" {
+			continue
+		}
+		status.Ready, _, _ = unstructured.NestedString(condition, "status")
+		status.Reason, _, _ = unstructured.NestedString(condition, "reason")
+		status.Message, _, _ = unstructured.NestedString(condition, "message")
+	}
+
+	return status
+}
+
+// latest returns the most recent of times.
+func latest(times ...time.Time) time.Time {
+	var result time.Time
+	for _, t := range times {
+		if t.After(result) {
+			result = t
+		}
+	}
+	return result
+}
@@ -0,0 +1,152 @@
+package pulse
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const metricsNamespace = "pulse"
+
+var (
+	upDesc = prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "", "up"),
+		"Whether the last pulse succeeded.", nil, nil)
+	healthLevelDesc = prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "", "health_level"),
+		"Overall health of the last pulse: 0 healthy, 1 warning, 2 critical.", nil, nil)
+	scrapeDurationDesc = prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "", "scrape_duration_seconds"),
+		"Time the last pulse took to read and analyze the cluster.", nil, nil)
+	lastRefreshDesc = prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "", "last_refresh_timestamp_seconds"),
+		"Unix time the last pulse finished.", nil, nil)
+	restartsDesc = prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "", "restarts"),
+		"Container restarts within the time window.", []string{"namespace"}, nil)
+	podsDesc = prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "", "pods"),
+		"Pods by phase.", []string{"phase"}, nil)
+	offenderRestartsDesc = prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "", "top_offender_restarts"),
+		"Total restarts of the pods with the most restarts.", []string{"namespace", "pod"}, nil)
+	signalDesc = prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "", "signal"),
+		"Counts graded by the health policy.", []string{"signal"}, nil)
+	signalLevelDesc = prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "", "signal_level"),
+		"Grade of each health policy signal: 0 healthy, 1 warning, 2 critical.", []string{"signal"}, nil)
+	timeWindowDesc = prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "", "time_window_seconds"),
+		"Time window restarts are counted in.", nil, nil)
+)
+
+// Exporter exposes the latest pulse as Prometheus metrics. Pulses are taken
+// by Refresh rather than on scrape, so scrapes are cheap and every scraper
+// sees the same result.
+type Exporter struct {
+	service           *Service
+	timeWindowMinutes int
+	podAmount         int
+	namespace         string
+
+	mu       sync.Mutex
+	health   *ClusterHealth
+	err      error
+	duration time.Duration
+	finished time.Time
+}
+
+func NewExporter(service *Service, timeWindowMinutes int, podAmount int, namespace string) *Exporter {
+	return &Exporter{
+		service:           service,
+		timeWindowMinutes: timeWindowMinutes,
+		podAmount:         podAmount,
+		namespace:         namespace,
+	}
+}
+
+// Refresh takes a new pulse. When it fails the previous result keeps being
+// exported with pulse_up at 0.
+func (e *Exporter) Refresh(ctx context.Context) error {
+	start := time.Now()
+	health, err := e.service.GetClusterHealth(ctx, e.timeWindowMinutes, e.podAmount, e.namespace)
+	finished := time.Now()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.err = err
+	e.duration = finished.Sub(start)
+	e.finished = finished
+	if err == nil {
+		e.health = &health
+	}
+	return err
+}
+
+// Handler serves the metrics of this exporter alone, without the Go runtime
+// metrics of the default registry.
+func (e *Exporter) Handler() http.Handler {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(e)
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		upDesc, healthLevelDesc, scrapeDurationDesc, lastRefreshDesc, restartsDesc,
+		podsDesc, offenderRestartsDesc, signalDesc, signalLevelDesc, timeWindowDesc,
+	} {
+		ch <- desc
+	}
+}
+
+func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	up := 1.0
+	if e.err != nil || e.health == nil {
+		up = 0
+	}
+	ch <- prometheus.MustNewConstMetric(upDesc, prometheus.GaugeValue, up)
+
+	if !e.finished.IsZero() {
+		ch <- prometheus.MustNewConstMetric(scrapeDurationDesc, prometheus.GaugeValue, e.duration.Seconds())
+		ch <- prometheus.MustNewConstMetric(lastRefreshDesc, prometheus.GaugeValue, float64(e.finished.Unix()))
+	}
+
+	health := e.health
+	if health == nil {
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(healthLevelDesc, prometheus.GaugeValue, float64(health.Status.ExitCode()))
+	ch <- prometheus.MustNewConstMetric(timeWindowDesc, prometheus.GaugeValue, float64(health.TimeWindow*60))
+
+	restarts := make(map[string]int32)
+	for _, pod := range health.RecentRestartPods {
+		restarts[pod.Namespace] += pod.RecentRestarts
+	}
+	for namespace, count := range restarts {
+		ch <- prometheus.MustNewConstMetric(restartsDesc, prometheus.GaugeValue, float64(count), namespace)
+	}
+
+	distribution := health.PodStatusDistribution
+	for phase, count := range map[string]int{
+		"Running":   distribution.Running,
+		"Pending":   distribution.Pending,
+		"Failed":    distribution.Failed,
+		"Succeeded": distribution.Succeeded,
+		"Unknown":   distribution.Unknown,
+	} {
+		ch <- prometheus.MustNewConstMetric(podsDesc, prometheus.GaugeValue, float64(count), phase)
+	}
+
+	for _, offender := range health.TopOffenders {
+		if offender.Restarts == 0 {
+			break
+		}
+		ch <- prometheus.MustNewConstMetric(offenderRestartsDesc, prometheus.GaugeValue, float64(offender.Restarts), offender.Namespace, offender.Name)
+	}
+
+	for _, signal := range health.Signals {
+		ch <- prometheus.MustNewConstMetric(signalDesc, prometheus.GaugeValue, float64(signal.Value), signal.Name)
+		ch <- prometheus.MustNewConstMetric(signalLevelDesc, prometheus.GaugeValue, float64(signal.Status.ExitCode()), signal.Name)
+	}
+}
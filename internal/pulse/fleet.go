@@ -0,0 +1,143 @@
+package pulse
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// Fleet runs the pulse against several kubeconfig contexts concurrently.
+type Fleet struct {
+	contexts   []string
+	newService func(kubeContext string) (*Service, error)
+	formatter  Formatter
+	policy     *HealthPolicy
+}
+
+func NewFleet(opts ClientOptions, contexts []string) *Fleet {
+	return &Fleet{
+		contexts: contexts,
+		newService: func(kubeContext string) (*Service, error) {
+			contextOpts := opts
+			contextOpts.Context = kubeContext
+			return NewService(contextOpts)
+		},
+		formatter: NewTextFormatter(),
+	}
+}
+
+func NewFleetWithServices(services map[string]*Service) *Fleet {
+	contexts := make([]string, 0, len(services))
+	for kubeContext := range services {
+		contexts = append(contexts, kubeContext)
+	}
+	sort.Strings(contexts)
+
+	return &Fleet{
+		contexts: contexts,
+		newService: func(kubeContext string) (*Service, error) {
+			return services[kubeContext], nil
+		},
+		formatter: NewTextFormatter(),
+	}
+}
+
+// ListContexts returns the context names of the kubeconfig the options resolve to.
+func ListContexts(opts ClientOptions) ([]string, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = opts.Kubeconfig
+
+	config, err := loadingRules.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	contexts := make([]string, 0, len(config.Contexts))
+	for name := range config.Contexts {
+		contexts = append(contexts, name)
+	}
+	sort.Strings(contexts)
+
+	return contexts, nil
+}
+
+// SetOutput selects the registered formatter GetFleetPulse renders with.
+func (f *Fleet) SetOutput(output string) error {
+	formatter, err := NewFormatter(output)
+	if err != nil {
+		return err
+	}
+	f.formatter = formatter
+	return nil
+}
+
+// SetPolicy grades every cluster of the fleet with the same health policy.
+func (f *Fleet) SetPolicy(policy *HealthPolicy) {
+	f.policy = policy
+}
+
+func (f *Fleet) GetFleetPulse(ctx context.Context, timeWindowMinutes int, podAmount int, namespace string) (string, error) {
+	return f.Format(f.GetFleetHealth(ctx, timeWindowMinutes, podAmount, namespace))
+}
+
+// Format renders fleet with the formatter selected by SetOutput.
+func (f *Fleet) Format(fleet FleetHealth) (string, error) {
+	return f.formatter.FormatFleetHealth(fleet)
+}
+
+// GetFleetHealth queries every context in parallel. A context that fails is
+// recorded with its error instead of aborting the others, and counts as
+// CRITICAL towards the fleet status.
+func (f *Fleet) GetFleetHealth(ctx context.Context, timeWindowMinutes int, podAmount int, namespace string) FleetHealth {
+	results := make([]ClusterResult, len(f.contexts))
+
+	var wg sync.WaitGroup
+	for i, kubeContext := range f.contexts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = f.pulseContext(ctx, kubeContext, timeWindowMinutes, podAmount, namespace)
+		}()
+	}
+	wg.Wait()
+
+	fleet := FleetHealth{
+		Status:   StatusHealthy,
+		Clusters: results,
+	}
+	for _, result := range results {
+		status := StatusCritical
+		if result.Health != nil {
+			status = result.Health.Status
+		}
+		if status.severity() > fleet.Status.severity() {
+			fleet.Status = status
+		}
+	}
+
+	return fleet
+}
+
+func (f *Fleet) pulseContext(ctx context.Context, kubeContext string, timeWindowMinutes int, podAmount int, namespace string) ClusterResult {
+	result := ClusterResult{Context: kubeContext}
+
+	service, err := f.newService(kubeContext)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if f.policy != nil {
+		service.SetPolicy(f.policy)
+	}
+
+	health, err := service.GetClusterHealth(ctx, timeWindowMinutes, podAmount, namespace)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Health = &health
+	return result
+}
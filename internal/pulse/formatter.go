@@ -1,113 +1,165 @@
 package pulse
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	OutputText     = "text"
+	OutputJSON     = "json"
+	OutputYAML     = "yaml"
+	OutputMarkdown = "markdown"
+	OutputNagios   = "nagios"
+)
+
+// Formatter renders a ClusterHealth, or a FleetHealth spanning several
+// clusters, for a particular output format.
+type Formatter interface {
+	FormatClusterHealth(health ClusterHealth) (string, error)
+	FormatFleetHealth(fleet FleetHealth) (string, error)
+}
 
-type Formatter struct{}
+var formatters = map[string]func() Formatter{
+	OutputText:     func() Formatter { return NewTextFormatter() },
+	OutputJSON:     func() Formatter { return NewJSONFormatter() },
+	OutputYAML:     func() Formatter { return NewYAMLFormatter() },
+	OutputMarkdown: func() Formatter { return NewMarkdownFormatter() },
+	OutputNagios:   func() Formatter { return NewNagiosFormatter() },
+}
 
-func NewFormatter() *Formatter {
-	return &Formatter{}
+// RegisterFormatter makes a formatter selectable by name through NewFormatter.
+// Registering an existing name replaces it.
+func RegisterFormatter(name string, factory func() Formatter) {
+	formatters[name] = factory
 }
 
-func (f *Formatter) FormatClusterHealth(health ClusterHealth) string {
-	var statusEmoji, statusText string
-	if health.RecentRestarts == 0 {
-		statusEmoji = "💚"
-		statusText = "HEALTHY"
-	} else if health.RecentRestarts <= 5 {
-		statusEmoji = "⚠️"
-		statusText = "WARNING"
-	} else {
-		statusEmoji = "🚨"
-		statusText = "CRITICAL"
+// NewFormatter returns the formatter registered under name.
+func NewFormatter(name string) (Formatter, error) {
+	factory, ok := formatters[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported output format %q (supported: %s)", name, strings.Join(FormatterNames(), ", "))
 	}
+	return factory(), nil
+}
 
-	output := fmt.Sprintf("\n%s %s - Cluster Pulse\n", statusEmoji, statusText)
-	output += "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n"
-
-	restartEmoji := "🔄"
-	if health.RecentRestarts == 0 {
-		restartEmoji = "✅"
+// FormatterNames lists the registered output formats in alphabetical order.
+func FormatterNames() []string {
+	names := make([]string, 0, len(formatters))
+	for name := range formatters {
+		names = append(names, name)
 	}
-	output += fmt.Sprintf("%s Recent restarts (%dm): %d", restartEmoji, health.TimeWindow, health.RecentRestarts)
-
-	if len(health.RecentRestartPods) > 0 {
-		output += " ("
-		for i, pod := range health.RecentRestartPods {
-			if i > 0 {
-				output += ", "
-			}
-			podName := pod.Name
-			if len(podName) > 15 {
-				podName = podName[:12] + "..."
-			}
-			output += fmt.Sprintf("%s/%s", pod.Namespace, podName)
-		}
-		output += ")"
+	sort.Strings(names)
+	return names
+}
+
+// pendingPodsShown caps the pending pods listed in the human-readable
+// formats; JSON and YAML carry all of them.
+const pendingPodsShown = 10
+
+// formatAge renders a duration the way kubectl prints ages, e.g. 45s, 12m,
+// 3h5m or 2d4h.
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd%dh", int(d.Hours())/24, int(d.Hours())%24)
 	}
-	output += "\n"
-
-	output += f.formatPodStatusDistribution(health.PodStatusDistribution)
-
-	if len(health.TopOffenders) > 0 && health.TopOffenders[0].Restarts > 0 {
-		output += "\n🔥 Top problematic pods:\n"
-		for _, offender := range health.TopOffenders {
-			if offender.Restarts == 0 {
-				break
-			}
-
-			podName := offender.Name
-			if len(podName) > 30 {
-				podName = podName[:27] + "..."
-			}
-
-			severity := "🟡"
-			if offender.Restarts > 100 {
-				severity = "🔴"
-			} else if offender.Restarts > 10 {
-				severity = "🟠"
-			}
-
-			output += fmt.Sprintf("   %s %s/%s (%d restarts)\n", severity, offender.Namespace, podName, offender.Restarts)
-		}
-	} else {
-		output += "\n✨ No problematic pods detected\n"
+}
+
+// eventObjectsShown caps the objects listed per event reason in the
+// human-readable formats; JSON and YAML carry all of them.
+const eventObjectsShown = 3
+
+// eventObjectName names the object an event is about as namespace/name, or
+// just its name when it is cluster-scoped.
+func eventObjectName(object EventObject) string {
+	if object.Namespace == "" {
+		return object.Name
 	}
+	return object.Namespace + "/" + object.Name
+}
 
-	output += "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"
+// formatSelector renders a label selector the way kubectl prints it, e.g.
+// app=web,tier=frontend.
+func formatSelector(selector map[string]string) string {
+	pairs := make([]string, 0, len(selector))
+	for key, value := range selector {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
 
-	return output
+// certificateExpiry describes when a certificate issue's certificate expires
+// or expired relative to now, e.g. "expires in 5d2h" or "expired 3h0m ago".
+func certificateExpiry(issue CertificateIssue) string {
+	if issue.NotAfter.IsZero() {
+		return ""
+	}
+	if remaining := time.Until(issue.NotAfter); remaining > 0 {
+		return "expires in " + formatAge(remaining)
+	}
+	return "expired " + formatAge(time.Since(issue.NotAfter)) + " ago"
 }
 
-func (f *Formatter) formatPodStatusDistribution(distribution PodStatusDistribution) string {
-	if distribution.Total == 0 {
-		return "📊 Pod Status: No pods found\n\n"
+// formatAutoscalerMetrics renders each metric as current/target, e.g.
+// "cpu 92%/70%, memory 60%/80%".
+func formatAutoscalerMetrics(metrics []AutoscalerMetric) string {
+	parts := make([]string, 0, len(metrics))
+	for _, metric := range metrics {
+		parts = append(parts, fmt.Sprintf("%s %s/%s", metric.Name, metric.Current, metric.Target))
 	}
+	return strings.Join(parts, ", ")
+}
 
-	output := "📊 Pod Status Distribution:\n"
-
-	// Define statuses with their emojis and order
-	statuses := []struct {
-		name  string
-		count int
-		emoji string
-	}{
-		{"Running", distribution.Running, "🟢"},
-		{"Pending", distribution.Pending, "🟡"},
-		{"Failed", distribution.Failed, "🔴"},
-		{"Succeeded", distribution.Succeeded, "✅"},
-		{"Unknown", distribution.Unknown, "❓"},
+// storageIssueName names the object of a storage issue as namespace/name, or
+// just its name when it is cluster-scoped.
+func storageIssueName(issue StorageIssue) string {
+	if issue.Namespace == "" {
+		return issue.Name
 	}
+	return issue.Namespace + "/" + issue.Name
+}
 
-	for _, status := range statuses {
-		if status.count > 0 {
-			percentage := distribution.GetPercentage(status.name)
-			output += fmt.Sprintf("   %s %s: %d (%.1f%%)\n",
-				status.emoji, status.name, status.count, percentage)
-		}
+// storageIssueCritical tells the issues that lose or block access to data
+// from those that only need attention, such as Released volumes.
+func storageIssueCritical(issue StorageIssue) bool {
+	switch issue.Status {
+	case "Lost", "Failed", StorageAttachError, "FailedAttachVolume", "FailedMount":
+		return true
+	default:
+		return false
 	}
+}
 
-	// Add total count
-	output += fmt.Sprintf("   📈 Total: %d pods\n\n", distribution.Total)
+// truncate shortens s to at most n runes on a single line.
+func truncate(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if runes := []rune(s); len(runes) > n {
+		return string(runes[:n-3]) + "..."
+	}
+	return s
+}
 
-	return output
+// sortedReasons orders a reason histogram by count, most frequent first.
+func sortedReasons(reasons map[string]int) []string {
+	names := make([]string, 0, len(reasons))
+	for name := range reasons {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if reasons[names[i]] != reasons[names[j]] {
+			return reasons[names[i]] > reasons[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	return names
 }
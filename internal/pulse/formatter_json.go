@@ -0,0 +1,104 @@
+package pulse
+
+import "encoding/json"
+
+// JSONSchemaVersion identifies the layout of the JSON and YAML documents.
+// Bump it on any breaking change so scripts consuming the output can detect it.
+const JSONSchemaVersion = "pulse/v2"
+
+type jsonReport struct {
+	SchemaVersion string `json:"schemaVersion"`
+	ClusterHealth
+}
+
+func newJSONReport(health ClusterHealth) jsonReport {
+	// Emit empty lists rather than null so consumers can iterate unconditionally
+	health.RecentRestartPods = emptyIfNil(health.RecentRestartPods)
+	health.TopOffenders = emptyIfNil(health.TopOffenders)
+	health.OOMKills = emptyIfNil(health.OOMKills)
+	health.PendingPods = emptyIfNil(health.PendingPods)
+	health.DegradedWorkloads = emptyIfNil(health.DegradedWorkloads)
+	if health.ContainerReasons == nil {
+		health.ContainerReasons = map[string]int{}
+	}
+	health.StuckRollouts = emptyIfNil(health.StuckRollouts)
+	health.Autoscalers = emptyIfNil(health.Autoscalers)
+	health.UnhealthyServices = emptyIfNil(health.UnhealthyServices)
+	health.RouteIssues = emptyIfNil(health.RouteIssues)
+	if health.Certificates != nil {
+		certificates := *health.Certificates
+		certificates.Issues = emptyIfNil(certificates.Issues)
+		health.Certificates = &certificates
+	}
+	health.WarningEvents = emptyIfNil(health.WarningEvents)
+	health.StorageIssues = emptyIfNil(health.StorageIssues)
+
+	if health.Nodes != nil {
+		nodes := *health.Nodes
+		nodes.NotReadyNodes = emptyIfNil(nodes.NotReadyNodes)
+		nodes.CordonedNodes = emptyIfNil(nodes.CordonedNodes)
+		nodes.MemoryPressure = emptyIfNil(nodes.MemoryPressure)
+		nodes.DiskPressure = emptyIfNil(nodes.DiskPressure)
+		nodes.PIDPressure = emptyIfNil(nodes.PIDPressure)
+		nodes.NetworkUnavailable = emptyIfNil(nodes.NetworkUnavailable)
+		health.Nodes = &nodes
+	}
+
+	return jsonReport{
+		SchemaVersion: JSONSchemaVersion,
+		ClusterHealth: health,
+	}
+}
+
+type jsonFleetReport struct {
+	SchemaVersion string `json:"schemaVersion"`
+	FleetHealth
+}
+
+func newJSONFleetReport(fleet FleetHealth) jsonFleetReport {
+	clusters := make([]ClusterResult, len(fleet.Clusters))
+	for i, cluster := range fleet.Clusters {
+		if cluster.Health != nil {
+			health := newJSONReport(*cluster.Health).ClusterHealth
+			cluster.Health = &health
+		}
+		clusters[i] = cluster
+	}
+	fleet.Clusters = clusters
+
+	return jsonFleetReport{
+		SchemaVersion: JSONSchemaVersion,
+		FleetHealth:   fleet,
+	}
+}
+
+func emptyIfNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
+
+type JSONFormatter struct{}
+
+func NewJSONFormatter() *JSONFormatter {
+	return &JSONFormatter{}
+}
+
+func (f *JSONFormatter) FormatClusterHealth(health ClusterHealth) (string, error) {
+	data, err := json.MarshalIndent(newJSONReport(health), "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+func (f *JSONFormatter) FormatFleetHealth(fleet FleetHealth) (string, error) {
+	data, err := json.MarshalIndent(newJSONFleetReport(fleet), "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
@@ -0,0 +1,317 @@
+package pulse
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type MarkdownFormatter struct{}
+
+func NewMarkdownFormatter() *MarkdownFormatter {
+	return &MarkdownFormatter{}
+}
+
+func (f *MarkdownFormatter) FormatClusterHealth(health ClusterHealth) (string, error) {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "## %s %s - Cluster Pulse\n\n", statusEmoji(health.Status), health.Status)
+	if len(health.Incomplete) > 0 {
+		fmt.Fprintf(&b, "> ⏳ **Incomplete:** %s timed out\n\n", strings.Join(health.Incomplete, ", "))
+	}
+	if len(health.Forbidden) > 0 {
+		fmt.Fprintf(&b, "> 🔒 **Not checked:** %s not permitted\n\n", strings.Join(health.Forbidden, ", "))
+	}
+	fmt.Fprintf(&b, "**Recent restarts (%dm):** %d\n\n", health.TimeWindow, health.RecentRestarts)
+
+	if len(health.RecentRestartPods) > 0 {
+		b.WriteString("| Namespace | Pod | Restarts in window | Total restarts | Last restart |\n")
+		b.WriteString("| --- | --- | ---: | ---: | --- |\n")
+		for _, pod := range health.RecentRestartPods {
+			fmt.Fprintf(&b, "| %s | %s | %d | %d | %s |\n",
+				markdownEscape(pod.Namespace), markdownEscape(pod.Name), pod.RecentRestarts, pod.Restarts, pod.LastRestart.UTC().Format("2006-01-02 15:04:05 UTC"))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("### Pod status distribution\n\n")
+	distribution := health.PodStatusDistribution
+	if distribution.Total == 0 {
+		b.WriteString("No pods found\n\n")
+	} else {
+		b.WriteString("| Status | Pods | Share |\n")
+		b.WriteString("| --- | ---: | ---: |\n")
+		statuses := []struct {
+			name  string
+			count int
+		}{
+			{"Running", distribution.Running},
+			{"Pending", distribution.Pending},
+			{"Failed", distribution.Failed},
+			{"Succeeded", distribution.Succeeded},
+			{"Unknown", distribution.Unknown},
+		}
+		for _, status := range statuses {
+			if status.count > 0 {
+				fmt.Fprintf(&b, "| %s | %d | %.1f%% |\n", status.name, status.count, distribution.GetPercentage(status.name))
+			}
+		}
+		fmt.Fprintf(&b, "| **Total** | **%d** | |\n\n", distribution.Total)
+	}
+
+	if len(health.ContainerReasons) > 0 {
+		b.WriteString("| Container reason | Containers |\n")
+		b.WriteString("| --- | ---: |\n")
+		for _, reason := range sortedReasons(health.ContainerReasons) {
+			fmt.Fprintf(&b, "| %s | %d |\n", reason, health.ContainerReasons[reason])
+		}
+		b.WriteString("\n")
+	}
+
+	if len(health.PendingPods) > 0 {
+		b.WriteString("### Pending pods\n\n")
+		b.WriteString("| Namespace | Pod | Pending for | Cause | Message |\n")
+		b.WriteString("| --- | --- | --- | --- | --- |\n")
+		for i, pod := range health.PendingPods {
+			if i == pendingPodsShown {
+				fmt.Fprintf(&b, "\n…and %d more\n", len(health.PendingPods)-pendingPodsShown)
+				break
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n",
+				markdownEscape(pod.Namespace), markdownEscape(pod.Pod), formatAge(time.Since(pod.Since)), pod.Cause, markdownEscape(truncate(pod.Message, 120)))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(health.OOMKills) > 0 {
+		b.WriteString("### OOM kills\n\n")
+		b.WriteString("| Namespace | Pod | Container | Memory request | Memory limit | OOM kills in window (est.) | Total restarts | Last killed at |\n")
+		b.WriteString("| --- | --- | --- | --- | --- | ---: | ---: | --- |\n")
+		for _, kill := range health.OOMKills {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | ~%d | %d | %s |\n",
+				markdownEscape(kill.Namespace), markdownEscape(kill.Pod), markdownEscape(kill.Container),
+				kill.MemoryRequest, kill.MemoryLimit, kill.Kills, kill.Restarts, kill.KilledAt.UTC().Format("2006-01-02 15:04:05 UTC"))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("### Nodes\n\n")
+	if nodes := health.Nodes; nodes == nil && skipReason(health, SectionNodes) == "timed out" {
+		b.WriteString("Not checked (timed out)\n\n")
+	} else if nodes == nil {
+		b.WriteString("Not permitted to list nodes\n\n")
+	} else if nodes.Total == 0 {
+		b.WriteString("No nodes found\n\n")
+	} else {
+		fmt.Fprintf(&b, "**%d/%d Ready**, %d NotReady, %d Unknown, %d cordoned\n\n",
+			nodes.Ready, nodes.Total, nodes.NotReady, nodes.Unknown, nodes.SchedulingDisabled)
+
+		conditions := []struct {
+			name  string
+			nodes []string
+		}{
+			{"NotReady", nodes.NotReadyNodes},
+			{"MemoryPressure", nodes.MemoryPressure},
+			{"DiskPressure", nodes.DiskPressure},
+			{"PIDPressure", nodes.PIDPressure},
+			{"NetworkUnavailable", nodes.NetworkUnavailable},
+			{"SchedulingDisabled", nodes.CordonedNodes},
+		}
+		var rows []string
+		for _, condition := range conditions {
+			if len(condition.nodes) > 0 {
+				rows = append(rows, fmt.Sprintf("| %s | %s |\n", condition.name, markdownEscape(strings.Join(condition.nodes, ", "))))
+			}
+		}
+		if len(rows) > 0 {
+			b.WriteString("| Condition | Nodes |\n")
+			b.WriteString("| --- | --- |\n")
+			b.WriteString(strings.Join(rows, ""))
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("### Degraded workloads\n\n")
+	if skipped := skipReason(health, SectionWorkloads); skipped != "" {
+		fmt.Fprintf(&b, "Not checked (%s)\n\n", skipped)
+	} else if len(health.DegradedWorkloads) == 0 {
+		b.WriteString("All replicas available\n\n")
+	} else {
+		b.WriteString("| Namespace | Kind | Name | Ready | Available | Desired |\n")
+		b.WriteString("| --- | --- | --- | ---: | ---: | ---: |\n")
+		for _, workload := range health.DegradedWorkloads {
+			fmt.Fprintf(&b, "| %s | %s | %s | %d | %d | %d |\n",
+				markdownEscape(workload.Namespace), workload.Kind, markdownEscape(workload.Name), workload.Ready, workload.Available, workload.Desired)
+		}
+		b.WriteString("\n")
+	}
+
+	if len(health.StuckRollouts) > 0 {
+		b.WriteString("### Stuck rollouts\n\n")
+		b.WriteString("| Namespace | Deployment | Revision | ReplicaSet | Reason | Message |\n")
+		b.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+		for _, rollout := range health.StuckRollouts {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n",
+				markdownEscape(rollout.Namespace), markdownEscape(rollout.Name), rollout.Revision,
+				markdownEscape(rollout.ReplicaSet), rollout.Reason, markdownEscape(rollout.Message))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(health.Autoscalers) > 0 {
+		b.WriteString("### Autoscaler issues\n\n")
+		b.WriteString("| Namespace | HPA | Target | Replicas | Min | Max | Metrics (current/target) | Problems | Message |\n")
+		b.WriteString("| --- | --- | --- | ---: | ---: | ---: | --- | --- | --- |\n")
+		for _, autoscaler := range health.Autoscalers {
+			fmt.Fprintf(&b, "| %s | %s | %s | %d | %d | %d | %s | %s | %s |\n",
+				markdownEscape(autoscaler.Namespace), markdownEscape(autoscaler.Name), markdownEscape(autoscaler.Target),
+				autoscaler.CurrentReplicas, autoscaler.MinReplicas, autoscaler.MaxReplicas,
+				markdownEscape(formatAutoscalerMetrics(autoscaler.Metrics)), strings.Join(autoscaler.Problems, ", "),
+				markdownEscape(truncate(autoscaler.Message, 120)))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(health.UnhealthyServices) > 0 {
+		b.WriteString("### Unhealthy services\n\n")
+		b.WriteString("| Namespace | Service | Ready endpoints | Endpoints | Matching pods | Selector |\n")
+		b.WriteString("| --- | --- | ---: | ---: | ---: | --- |\n")
+		for _, service := range health.UnhealthyServices {
+			fmt.Fprintf(&b, "| %s | %s | %d | %d | %d | %s |\n",
+				markdownEscape(service.Namespace), markdownEscape(service.Name), service.ReadyEndpoints,
+				service.Endpoints, service.Pods, markdownEscape(formatSelector(service.Selector)))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(health.RouteIssues) > 0 {
+		b.WriteString("### Route issues\n\n")
+		b.WriteString("| Namespace | Kind | Name | Backend | Reason | Message |\n")
+		b.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+		for _, issue := range health.RouteIssues {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n",
+				markdownEscape(issue.Namespace), issue.Kind, markdownEscape(issue.Name),
+				markdownEscape(issue.Backend), issue.Reason, markdownEscape(truncate(issue.Message, 120)))
+		}
+		b.WriteString("\n")
+	}
+
+	if certificates := health.Certificates; certificates != nil && (len(certificates.Issues) > 0 || certificates.SecretsForbidden) {
+		fmt.Fprintf(&b, "### Certificates (horizon %s)\n\n", certificates.Horizon)
+		if certificates.SecretsForbidden {
+			b.WriteString("Not permitted to list Secrets, only cert-manager Certificates checked\n\n")
+		}
+		if len(certificates.Issues) > 0 {
+			b.WriteString("| Namespace | Kind | Name | Subject | Status | Expiry | Message |\n")
+			b.WriteString("| --- | --- | --- | --- | --- | --- | --- |\n")
+			for _, issue := range certificates.Issues {
+				fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s | %s |\n",
+					markdownEscape(issue.Namespace), issue.Kind, markdownEscape(issue.Name), markdownEscape(issue.Subject),
+					issue.Status, certificateExpiry(issue), markdownEscape(truncate(issue.Message, 120)))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	if len(health.StorageIssues) > 0 {
+		b.WriteString("### Storage issues\n\n")
+		b.WriteString("| Kind | Name | Status | Age | Message |\n")
+		b.WriteString("| --- | --- | --- | --- | --- |\n")
+		for _, issue := range health.StorageIssues {
+			age := ""
+			if !issue.Since.IsZero() {
+				age = formatAge(time.Since(issue.Since))
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n",
+				issue.Kind, markdownEscape(storageIssueName(issue)), issue.Status, age, markdownEscape(truncate(issue.Message, 120)))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(health.WarningEvents) > 0 {
+		fmt.Fprintf(&b, "### Warning events (%dm)\n\n", health.TimeWindow)
+		b.WriteString("| Reason | Total | Object | Count | Latest message |\n")
+		b.WriteString("| --- | ---: | --- | ---: | --- |\n")
+		for _, reason := range health.WarningEvents {
+			for i, object := range reason.Objects {
+				if i == eventObjectsShown {
+					break
+				}
+				fmt.Fprintf(&b, "| %s | %d | %s %s | %d | %s |\n",
+					reason.Reason, reason.Count, object.Kind, markdownEscape(eventObjectName(object)), object.Count, markdownEscape(truncate(object.Message, 120)))
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("### Top problematic pods\n\n")
+	if len(health.TopOffenders) > 0 && health.TopOffenders[0].Restarts > 0 {
+		b.WriteString("| Namespace | Pod | Restarts | Containers |\n")
+		b.WriteString("| --- | --- | ---: | --- |\n")
+		for _, offender := range health.TopOffenders {
+			if offender.Restarts == 0 {
+				break
+			}
+
+			var containers []string
+			for _, container := range offender.Containers {
+				if container.Restarts == 0 {
+					continue
+				}
+				detail := fmt.Sprintf("%s: %d", container.Name, container.Restarts)
+				if container.Init {
+					detail += " (init)"
+				} else if container.Ephemeral {
+					detail += " (ephemeral)"
+				}
+				if container.Reason != "" {
+					detail += fmt.Sprintf(", exit %d %s", container.ExitCode, container.Reason)
+				}
+				containers = append(containers, detail)
+			}
+
+			fmt.Fprintf(&b, "| %s | %s | %d | %s |\n", markdownEscape(offender.Namespace), markdownEscape(offender.Name), offender.Restarts,
+				markdownEscape(strings.Join(containers, "<br>")))
+		}
+	} else {
+		b.WriteString("No problematic pods detected\n")
+	}
+
+	if len(health.Changes) > 0 {
+		b.WriteString("\n### Changes since last refresh\n\n")
+		b.WriteString("| Namespace | Pod | Change | Detail |\n")
+		b.WriteString("| --- | --- | --- | --- |\n")
+		for _, change := range health.Changes {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n",
+				markdownEscape(change.Namespace), markdownEscape(change.Pod), change.Change, markdownEscape(change.Detail))
+		}
+	}
+
+	return b.String(), nil
+}
+
+func (f *MarkdownFormatter) FormatFleetHealth(fleet FleetHealth) (string, error) {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "## %s %s - Fleet Pulse (%d clusters)\n\n", statusEmoji(fleet.Status), fleet.Status, len(fleet.Clusters))
+	b.WriteString("| Context | Status | Recent restarts | Pods | Pending | Failed |\n")
+	b.WriteString("| --- | --- | ---: | ---: | ---: | ---: |\n")
+	for _, cluster := range fleet.Clusters {
+		if cluster.Health == nil {
+			fmt.Fprintf(&b, "| %s | 🚨 ERROR: %s | | | | |\n", markdownEscape(cluster.Context), markdownEscape(cluster.Error))
+			continue
+		}
+
+		health := cluster.Health
+		fmt.Fprintf(&b, "| %s | %s %s | %d | %d | %d | %d |\n",
+			markdownEscape(cluster.Context), statusEmoji(health.Status), health.Status, health.RecentRestarts,
+			health.PodStatusDistribution.Total, health.PodStatusDistribution.Pending, health.PodStatusDistribution.Failed)
+	}
+
+	return b.String(), nil
+}
+
+// markdownEscape keeps cell values from breaking the surrounding table.
+func markdownEscape(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
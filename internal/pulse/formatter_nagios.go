@@ -0,0 +1,149 @@
+package pulse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// signalDescriptions words a signal's count for the Nagios status line, in
+// singular and plural.
+var signalDescriptions = map[string][2]string{
+	SignalRestarts:             {"recent restart", "recent restarts"},
+	SignalPendingPods:          {"pod Pending", "pods Pending"},
+	SignalFailedPods:           {"pod Failed", "pods Failed"},
+	SignalContainerReasons:     {"container with problems", "containers with problems"},
+	SignalOOMKills:             {"OOM kill", "OOM kills"},
+	SignalDegradedWorkloads:    {"workload degraded", "workloads degraded"},
+	SignalUnavailableWorkloads: {"workload unavailable", "workloads unavailable"},
+	SignalStuckRollouts:        {"rollout stuck", "rollouts stuck"},
+	SignalAutoscalerIssues:     {"autoscaler issue", "autoscaler issues"},
+	SignalDegradedServices:     {"service degraded", "services degraded"},
+	SignalUnavailableServices:  {"service without ready endpoints", "services without ready endpoints"},
+	SignalRouteIssues:          {"route issue", "route issues"},
+	SignalExpiringCertificates: {"certificate expiring", "certificates expiring"},
+	SignalExpiredCertificates:  {"certificate expired", "certificates expired"},
+	SignalWarningEvents:        {"warning event", "warning events"},
+	SignalStorageIssues:        {"storage issue", "storage issues"},
+	SignalNotReadyNodes:        {"node NotReady", "nodes NotReady"},
+	SignalPressureNodes:        {"node under pressure", "nodes under pressure"},
+	SignalIncompleteSections:   {"section timed out", "sections timed out"},
+}
+
+// NagiosFormatter renders the monitoring plugin format understood by Nagios,
+// Icinga and compatible checkers: a status line with perfdata after a pipe.
+// Use NagiosExitCode for the matching exit code.
+type NagiosFormatter struct{}
+
+func NewNagiosFormatter() *NagiosFormatter {
+	return &NagiosFormatter{}
+}
+
+func (f *NagiosFormatter) FormatClusterHealth(health ClusterHealth) (string, error) {
+	output := fmt.Sprintf("PULSE %s - %s", nagiosHealthState(health), f.summary(health))
+	if len(health.Incomplete) > 0 {
+		output += fmt.Sprintf(" (incomplete: %s timed out)", strings.Join(health.Incomplete, ", "))
+	}
+
+	var perfdata []string
+	for _, signal := range health.Signals {
+		perfdata = append(perfdata, fmt.Sprintf("%s=%d;%s;%s", signal.Name, signal.Value, nagiosBound(signal.Warning), nagiosBound(signal.Critical)))
+	}
+	if len(perfdata) > 0 {
+		output += " | " + strings.Join(perfdata, " ")
+	}
+
+	return output, nil
+}
+
+// summary lists the signals that are not healthy, critical ones first.
+func (f *NagiosFormatter) summary(health ClusterHealth) string {
+	var problems []string
+	for _, status := range []HealthStatus{StatusCritical, StatusWarning} {
+		for _, signal := range health.Signals {
+			if signal.Status != status {
+				continue
+			}
+			description := signalDescriptions[signal.Name]
+			noun := description[1]
+			if signal.Value == 1 {
+				noun = description[0]
+			}
+			problems = append(problems, fmt.Sprintf("%d %s", signal.Value, noun))
+		}
+	}
+
+	if len(problems) == 0 {
+		return fmt.Sprintf("%d pods, no problems detected", health.PodStatusDistribution.Total)
+	}
+	return strings.Join(problems, ", ")
+}
+
+// FormatFleetHealth puts the fleet on the status line and one line per
+// cluster in the plugin's long output.
+func (f *NagiosFormatter) FormatFleetHealth(fleet FleetHealth) (string, error) {
+	counts := make(map[string]int)
+	var problems, lines []string
+	for _, cluster := range fleet.Clusters {
+		if cluster.Health == nil {
+			counts["unreachable"]++
+			problems = append(problems, cluster.Context+" unreachable")
+			lines = append(lines, fmt.Sprintf("%s: UNKNOWN - %s", cluster.Context, cluster.Error))
+			continue
+		}
+
+		status := cluster.Health.Status
+		counts[strings.ToLower(nagiosState(status))]++
+		if status != StatusHealthy {
+			problems = append(problems, fmt.Sprintf("%s %s", cluster.Context, status))
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s - %s", cluster.Context, nagiosHealthState(*cluster.Health), f.summary(*cluster.Health)))
+	}
+
+	summary := fmt.Sprintf("%d clusters healthy", len(fleet.Clusters))
+	if len(problems) > 0 {
+		summary = strings.Join(problems, ", ")
+	}
+
+	output := fmt.Sprintf("PULSE %s - %s | clusters=%d;; ok=%d;; warning=%d;; critical=%d;; unreachable=%d;;",
+		nagiosState(fleet.Status), summary, len(fleet.Clusters),
+		counts["ok"], counts["warning"], counts["critical"], counts["unreachable"])
+	for _, line := range lines {
+		output += "\n" + line
+	}
+
+	return output, nil
+}
+
+// nagiosState names a status the way monitoring plugins do.
+// nagiosHealthState is the plugin state of a pulse: UNKNOWN when sections
+// timed out and nothing CRITICAL was found, as the check did not fully run,
+// and the state of its status otherwise.
+func nagiosHealthState(health ClusterHealth) string {
+	if len(health.Incomplete) > 0 && health.Status != StatusCritical {
+		return "UNKNOWN"
+	}
+	return nagiosState(health.Status)
+}
+
+// NagiosExitCode is the plugin exit code matching the state the Nagios
+// output reports for health.
+func NagiosExitCode(health ClusterHealth) int {
+	if nagiosHealthState(health) == "UNKNOWN" {
+		return ExitCodeError
+	}
+	return health.Status.ExitCode()
+}
+
+func nagiosState(status HealthStatus) string {
+	if status == StatusHealthy {
+		return "OK"
+	}
+	return string(status)
+}
+
+func nagiosBound(bound *int) string {
+	if bound == nil {
+		return ""
+	}
+	return fmt.Sprint(*bound)
+}
@@ -0,0 +1,495 @@
+package pulse
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type TextFormatter struct{}
+
+func NewTextFormatter() *TextFormatter {
+	return &TextFormatter{}
+}
+
+func (f *TextFormatter) FormatClusterHealth(health ClusterHealth) (string, error) {
+	output := fmt.Sprintf("\n%s %s - Cluster Pulse\n", statusEmoji(health.Status), health.Status)
+	output += "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n"
+
+	if len(health.Incomplete) > 0 {
+		output += fmt.Sprintf("⏳ Incomplete: %s timed out\n", strings.Join(health.Incomplete, ", "))
+	}
+	if len(health.Forbidden) > 0 {
+		output += fmt.Sprintf("🔒 Not checked: %s not permitted\n", strings.Join(health.Forbidden, ", "))
+	}
+
+	restartEmoji := "🔄"
+	if health.RecentRestarts == 0 {
+		restartEmoji = "✅"
+	}
+	output += fmt.Sprintf("%s Recent restarts (%dm): %d", restartEmoji, health.TimeWindow, health.RecentRestarts)
+
+	if len(health.RecentRestartPods) > 0 {
+		output += " ("
+		for i, pod := range health.RecentRestartPods {
+			if i > 0 {
+				output += ", "
+			}
+			podName := pod.Name
+			if len(podName) > 15 {
+				podName = podName[:12] + "..."
+			}
+			output += fmt.Sprintf("%s/%s ×%d", pod.Namespace, podName, pod.RecentRestarts)
+		}
+		output += ")"
+	}
+	output += "\n"
+
+	output += f.formatPodStatusDistribution(health.PodStatusDistribution, health.ContainerReasons)
+	output += f.formatPendingPods(health.PendingPods)
+	output += f.formatOOMKills(health.OOMKills)
+	output += f.formatNodeHealth(health.Nodes, skipReason(health, SectionNodes))
+	output += f.formatDegradedWorkloads(health.DegradedWorkloads, skipReason(health, SectionWorkloads))
+	output += f.formatStuckRollouts(health.StuckRollouts)
+	output += f.formatAutoscalers(health.Autoscalers)
+	output += f.formatUnhealthyServices(health.UnhealthyServices)
+	output += f.formatRouteIssues(health.RouteIssues)
+	output += f.formatCertificates(health.Certificates)
+	output += f.formatStorageIssues(health.StorageIssues)
+	output += f.formatWarningEvents(health.WarningEvents, health.TimeWindow)
+
+	if len(health.TopOffenders) > 0 && health.TopOffenders[0].Restarts > 0 {
+		output += "\n🔥 Top problematic pods:\n"
+		for _, offender := range health.TopOffenders {
+			if offender.Restarts == 0 {
+				break
+			}
+
+			podName := offender.Name
+			if len(podName) > 30 {
+				podName = podName[:27] + "..."
+			}
+
+			severity := "🟡"
+			switch offender.Severity {
+			case StatusCritical:
+				severity = "🔴"
+			case StatusWarning:
+				severity = "🟠"
+			}
+
+			output += fmt.Sprintf("   %s %s/%s (%d restarts)\n", severity, offender.Namespace, podName, offender.Restarts)
+			output += f.formatContainerRestarts(offender.Containers)
+		}
+	} else {
+		output += "\n✨ No problematic pods detected\n"
+	}
+
+	output += f.formatChanges(health.Changes)
+
+	output += "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"
+
+	return output, nil
+}
+
+func (f *TextFormatter) FormatFleetHealth(fleet FleetHealth) (string, error) {
+	output := fmt.Sprintf("\n%s %s - Fleet Pulse (%d clusters)\n", statusEmoji(fleet.Status), fleet.Status, len(fleet.Clusters))
+	output += "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n"
+
+	width := 0
+	for _, cluster := range fleet.Clusters {
+		width = max(width, len(cluster.Context))
+	}
+
+	for _, cluster := range fleet.Clusters {
+		if cluster.Health == nil {
+			output += fmt.Sprintf("🚨 %-*s  %-8s  %s\n", width, cluster.Context, "ERROR", cluster.Error)
+			continue
+		}
+
+		health := cluster.Health
+		output += fmt.Sprintf("%s %-*s  %-8s  %d recent restarts (%dm), %d pods (%d pending, %d failed)",
+			statusEmoji(health.Status), width, cluster.Context, health.Status,
+			health.RecentRestarts, health.TimeWindow,
+			health.PodStatusDistribution.Total, health.PodStatusDistribution.Pending, health.PodStatusDistribution.Failed)
+		if health.Nodes != nil {
+			output += fmt.Sprintf(", %d/%d nodes ready", health.Nodes.Ready, health.Nodes.Total)
+		}
+		if len(health.Incomplete) > 0 {
+			output += " (incomplete)"
+		}
+		output += "\n"
+	}
+
+	output += "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"
+
+	return output, nil
+}
+
+func (f *TextFormatter) formatPodStatusDistribution(distribution PodStatusDistribution, reasons map[string]int) string {
+	if distribution.Total == 0 {
+		return "📊 Pod Status: No pods found\n\n"
+	}
+
+	output := "📊 Pod Status Distribution:\n"
+
+	// Define statuses with their emojis and order
+	statuses := []struct {
+		name  string
+		count int
+		emoji string
+	}{
+		{"Running", distribution.Running, "🟢"},
+		{"Pending", distribution.Pending, "🟡"},
+		{"Failed", distribution.Failed, "🔴"},
+		{"Succeeded", distribution.Succeeded, "✅"},
+		{"Unknown", distribution.Unknown, "❓"},
+	}
+
+	for _, status := range statuses {
+		if status.count > 0 {
+			percentage := distribution.GetPercentage(status.name)
+			output += fmt.Sprintf("   %s %s: %d (%.1f%%)\n",
+				status.emoji, status.name, status.count, percentage)
+		}
+	}
+
+	// Add total count
+	output += fmt.Sprintf("   📈 Total: %d pods\n", distribution.Total)
+
+	if len(reasons) > 0 {
+		output += "   🩺 Container reasons:"
+		for i, reason := range sortedReasons(reasons) {
+			if i > 0 {
+				output += ","
+			}
+			output += fmt.Sprintf(" %s %d", reason, reasons[reason])
+		}
+		output += "\n"
+	}
+	output += "\n"
+
+	return output
+}
+
+func statusEmoji(status HealthStatus) string {
+	switch status {
+	case StatusHealthy:
+		return "💚"
+	case StatusWarning:
+		return "⚠️"
+	default:
+		return "🚨"
+	}
+}
+
+func (f *TextFormatter) formatNodeHealth(nodes *NodeHealth, skipped string) string {
+	if nodes == nil {
+		if skipped == "timed out" {
+			return "⏳ Nodes: not checked (timed out)\n"
+		}
+		return "🖥️  Nodes: not permitted to list nodes\n"
+	}
+	if nodes.Total == 0 {
+		return "🖥️  Nodes: No nodes found\n"
+	}
+
+	output := fmt.Sprintf("🖥️  Nodes: %d/%d Ready", nodes.Ready, nodes.Total)
+	if nodes.SchedulingDisabled > 0 {
+		output += fmt.Sprintf(", %d cordoned", nodes.SchedulingDisabled)
+	}
+	output += "\n"
+
+	conditions := []struct {
+		name  string
+		nodes []string
+		emoji string
+	}{
+		{"NotReady", nodes.NotReadyNodes, "🔴"},
+		{"MemoryPressure", nodes.MemoryPressure, "🟠"},
+		{"DiskPressure", nodes.DiskPressure, "🟠"},
+		{"PIDPressure", nodes.PIDPressure, "🟠"},
+		{"NetworkUnavailable", nodes.NetworkUnavailable, "🟠"},
+		{"SchedulingDisabled", nodes.CordonedNodes, "🚧"},
+	}
+
+	for _, condition := range conditions {
+		if len(condition.nodes) > 0 {
+			output += fmt.Sprintf("   %s %s: %s\n", condition.emoji, condition.name, strings.Join(condition.nodes, ", "))
+		}
+	}
+
+	return output
+}
+
+func (f *TextFormatter) formatDegradedWorkloads(workloads []WorkloadStatus, skipped string) string {
+	if skipped != "" {
+		return fmt.Sprintf("⏳ Workloads: not checked (%s)\n", skipped)
+	}
+	if len(workloads) == 0 {
+		return "✅ Workloads: all replicas available\n"
+	}
+
+	output := fmt.Sprintf("📉 Degraded workloads: %d\n", len(workloads))
+	for _, workload := range workloads {
+		severity := "🟡"
+		if workload.Available == 0 {
+			severity = "🔴"
+		}
+		output += fmt.Sprintf("   %s %s/%s %s (%d/%d ready, %d available)\n",
+			severity, workload.Namespace, workload.Name, workload.Kind, workload.Ready, workload.Desired, workload.Available)
+	}
+
+	return output
+}
+
+func (f *TextFormatter) formatStuckRollouts(rollouts []StuckRollout) string {
+	if len(rollouts) == 0 {
+		return ""
+	}
+
+	output := fmt.Sprintf("🚧 Stuck rollouts: %d\n", len(rollouts))
+	for _, rollout := range rollouts {
+		output += fmt.Sprintf("   🔴 %s/%s revision %s", rollout.Namespace, rollout.Name, rollout.Revision)
+		if rollout.ReplicaSet != "" {
+			output += fmt.Sprintf(" (%s)", rollout.ReplicaSet)
+		}
+		output += fmt.Sprintf(": %s", rollout.Reason)
+		if rollout.Message != "" {
+			output += fmt.Sprintf(" - %s", rollout.Message)
+		}
+		output += "\n"
+	}
+
+	return output
+}
+
+func (f *TextFormatter) formatAutoscalers(autoscalers []AutoscalerStatus) string {
+	if len(autoscalers) == 0 {
+		return ""
+	}
+
+	output := fmt.Sprintf("📈 Autoscaler issues: %d\n", len(autoscalers))
+	for _, autoscaler := range autoscalers {
+		output += fmt.Sprintf("   🟡 %s/%s → %s %d/%d replicas (min %d): %s",
+			autoscaler.Namespace, autoscaler.Name, autoscaler.Target, autoscaler.CurrentReplicas,
+			autoscaler.MaxReplicas, autoscaler.MinReplicas, strings.Join(autoscaler.Problems, ", "))
+		if metrics := formatAutoscalerMetrics(autoscaler.Metrics); metrics != "" {
+			output += " [" + metrics + "]"
+		}
+		if autoscaler.Message != "" {
+			output += " - " + truncate(autoscaler.Message, 100)
+		}
+		output += "\n"
+	}
+
+	return output
+}
+
+func (f *TextFormatter) formatUnhealthyServices(services []ServiceStatus) string {
+	if len(services) == 0 {
+		return ""
+	}
+
+	output := fmt.Sprintf("🔌 Unhealthy services: %d\n", len(services))
+	for _, service := range services {
+		severity := "🟡"
+		if service.ReadyEndpoints == 0 {
+			severity = "🔴"
+		}
+		output += fmt.Sprintf("   %s %s/%s: %d/%d endpoints ready, %d pods match %s\n",
+			severity, service.Namespace, service.Name, service.ReadyEndpoints, service.Endpoints, service.Pods, formatSelector(service.Selector))
+	}
+
+	return output
+}
+
+func (f *TextFormatter) formatRouteIssues(issues []RouteIssue) string {
+	if len(issues) == 0 {
+		return ""
+	}
+
+	output := fmt.Sprintf("🚦 Route issues: %d\n", len(issues))
+	for _, issue := range issues {
+		output += fmt.Sprintf("   🟡 %s %s/%s", issue.Kind, issue.Namespace, issue.Name)
+		if issue.Backend != "" {
+			output += " → " + issue.Backend
+		}
+		output += ": " + issue.Reason
+		if issue.Message != "" {
+			output += " - " + truncate(issue.Message, 100)
+		}
+		output += "\n"
+	}
+
+	return output
+}
+
+func (f *TextFormatter) formatCertificates(certificates *CertificateHealth) string {
+	if certificates == nil {
+		return ""
+	}
+
+	var output string
+	if len(certificates.Issues) > 0 {
+		output = fmt.Sprintf("🔐 Certificate issues: %d (horizon %s)\n", len(certificates.Issues), certificates.Horizon)
+		for _, issue := range certificates.Issues {
+			severity := "🟡"
+			if issue.Status == CertificateExpired {
+				severity = "🔴"
+			}
+			output += fmt.Sprintf("   %s %s %s/%s", severity, issue.Kind, issue.Namespace, issue.Name)
+			if issue.Subject != "" {
+				output += fmt.Sprintf(" (%s)", issue.Subject)
+			}
+			output += ": " + issue.Status
+			if expiry := certificateExpiry(issue); expiry != "" {
+				output += ", " + expiry
+			}
+			if issue.Message != "" {
+				output += " - " + truncate(issue.Message, 100)
+			}
+			output += "\n"
+		}
+	}
+	if certificates.SecretsForbidden {
+		output += "🔐 Certificates: not permitted to list Secrets, only cert-manager Certificates checked\n"
+	}
+
+	return output
+}
+
+func (f *TextFormatter) formatStorageIssues(issues []StorageIssue) string {
+	if len(issues) == 0 {
+		return ""
+	}
+
+	output := fmt.Sprintf("💾 Storage issues: %d\n", len(issues))
+	for _, issue := range issues {
+		severity := "🟡"
+		if storageIssueCritical(issue) {
+			severity = "🔴"
+		}
+		output += fmt.Sprintf("   %s %s %s %s", severity, issue.Kind, storageIssueName(issue), issue.Status)
+		if !issue.Since.IsZero() {
+			output += " for " + formatAge(time.Since(issue.Since))
+		}
+		if issue.Message != "" {
+			output += ": " + truncate(issue.Message, 100)
+		}
+		output += "\n"
+	}
+
+	return output
+}
+
+func (f *TextFormatter) formatWarningEvents(reasons []EventReason, timeWindow int) string {
+	if len(reasons) == 0 {
+		return ""
+	}
+
+	output := fmt.Sprintf("📣 Warning events (%dm):\n", timeWindow)
+	for _, reason := range reasons {
+		output += fmt.Sprintf("   ⚠️ %s ×%d (%d objects)\n", reason.Reason, reason.Count, len(reason.Objects))
+		for i, object := range reason.Objects {
+			if i == eventObjectsShown {
+				break
+			}
+			output += fmt.Sprintf("      └ %s %s ×%d: %s\n", object.Kind, eventObjectName(object), object.Count, truncate(object.Message, 80))
+		}
+	}
+
+	return output
+}
+
+func (f *TextFormatter) formatPendingPods(pending []PendingPod) string {
+	if len(pending) == 0 {
+		return ""
+	}
+
+	output := fmt.Sprintf("⏳ Pending pods: %d\n", len(pending))
+	for i, pod := range pending {
+		if i == pendingPodsShown {
+			output += fmt.Sprintf("   … and %d more\n", len(pending)-pendingPodsShown)
+			break
+		}
+		output += fmt.Sprintf("   🕐 %s/%s pending %s: %s", pod.Namespace, pod.Pod, formatAge(time.Since(pod.Since)), pod.Cause)
+		if pod.Message != "" {
+			output += " - " + truncate(pod.Message, 100)
+		}
+		output += "\n"
+	}
+
+	return output
+}
+
+func (f *TextFormatter) formatOOMKills(kills []OOMKill) string {
+	if len(kills) == 0 {
+		return ""
+	}
+
+	output := fmt.Sprintf("💥 OOM-killed containers: %d\n", len(kills))
+	for _, kill := range kills {
+		limit := kill.MemoryLimit
+		if limit == "" {
+			limit = "none"
+		}
+		request := kill.MemoryRequest
+		if request == "" {
+			request = "none"
+		}
+		output += fmt.Sprintf("   🔴 %s/%s [%s] limit %s, request %s (~%d OOM kills in window, %d restarts total)\n",
+			kill.Namespace, kill.Pod, kill.Container, limit, request, kill.Kills, kill.Restarts)
+	}
+
+	return output
+}
+
+// formatContainerRestarts drills down into the containers of a pod that have
+// restarted, so a flapping sidecar can be told apart from the app.
+func (f *TextFormatter) formatContainerRestarts(containers []ContainerStatus) string {
+	output := ""
+	for _, container := range containers {
+		if container.Restarts == 0 {
+			continue
+		}
+
+		name := container.Name
+		if container.Init {
+			name += " (init)"
+		} else if container.Ephemeral {
+			name += " (ephemeral)"
+		}
+
+		output += fmt.Sprintf("      └ %s: %d restarts", name, container.Restarts)
+		if container.Reason != "" {
+			output += fmt.Sprintf(", last exit %d %s", container.ExitCode, container.Reason)
+		}
+		output += "\n"
+	}
+	return output
+}
+
+func (f *TextFormatter) formatChanges(changes []PodChange) string {
+	if len(changes) == 0 {
+		return ""
+	}
+
+	output := "\n🔀 Changes since last refresh:\n"
+	for _, change := range changes {
+		emoji := map[string]string{
+			ChangePodCreated:   "➕",
+			ChangePodDeleted:   "➖",
+			ChangePhaseChanged: "🔁",
+			ChangeRestarted:    "🔄",
+		}[change.Change]
+
+		output += fmt.Sprintf("   %s %s/%s %s", emoji, change.Namespace, change.Pod, change.Change)
+		if change.Detail != "" {
+			output += fmt.Sprintf(" (%s)", change.Detail)
+		}
+		output += "\n"
+	}
+
+	return output
+}
@@ -0,0 +1,29 @@
+package pulse
+
+import "sigs.k8s.io/yaml"
+
+type YAMLFormatter struct{}
+
+func NewYAMLFormatter() *YAMLFormatter {
+	return &YAMLFormatter{}
+}
+
+// FormatClusterHealth renders the same document as the JSON formatter,
+// so both formats share field names and the schema version.
+func (f *YAMLFormatter) FormatClusterHealth(health ClusterHealth) (string, error) {
+	data, err := yaml.Marshal(newJSONReport(health))
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+func (f *YAMLFormatter) FormatFleetHealth(fleet FleetHealth) (string, error) {
+	data, err := yaml.Marshal(newJSONFleetReport(fleet))
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
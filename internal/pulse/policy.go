@@ -0,0 +1,355 @@
+package pulse
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Signal names, as used in policy files and monitoring output.
+const (
+	SignalRestarts             = "restarts"
+	SignalPendingPods          = "pendingPods"
+	SignalFailedPods           = "failedPods"
+	SignalContainerReasons     = "containerReasons"
+	SignalOOMKills             = "oomKills"
+	SignalDegradedWorkloads    = "degradedWorkloads"
+	SignalUnavailableWorkloads = "unavailableWorkloads"
+	SignalStuckRollouts        = "stuckRollouts"
+	SignalAutoscalerIssues     = "autoscalerIssues"
+	SignalDegradedServices     = "degradedServices"
+	SignalUnavailableServices  = "unavailableServices"
+	SignalRouteIssues          = "routeIssues"
+	SignalExpiringCertificates = "expiringCertificates"
+	SignalExpiredCertificates  = "expiredCertificates"
+	SignalWarningEvents        = "warningEvents"
+	SignalStorageIssues        = "storageIssues"
+	SignalNotReadyNodes        = "notReadyNodes"
+	SignalPressureNodes        = "pressureNodes"
+	SignalIncompleteSections   = "incompleteSections"
+	SignalOffenderRestarts     = "offenderRestarts"
+)
+
+// namespacedSignals are the signals counted per namespace, in the order they
+// are reported.
+var namespacedSignals = []string{
+	SignalRestarts,
+	SignalPendingPods,
+	SignalFailedPods,
+	SignalContainerReasons,
+	SignalOOMKills,
+	SignalDegradedWorkloads,
+	SignalUnavailableWorkloads,
+	SignalStuckRollouts,
+	SignalAutoscalerIssues,
+	SignalDegradedServices,
+	SignalUnavailableServices,
+	SignalRouteIssues,
+	SignalExpiringCertificates,
+	SignalExpiredCertificates,
+	SignalWarningEvents,
+	SignalStorageIssues,
+}
+
+// Threshold grades a count: above Warning it is WARNING and above Critical it
+// is CRITICAL. A nil bound is never crossed, so a signal with neither bound
+// does not affect the status.
+type Threshold struct {
+	Warning  *int `json:"warning,omitempty"`
+	Critical *int `json:"critical,omitempty"`
+}
+
+// Evaluate returns the status of count under the threshold.
+func (t Threshold) Evaluate(count int) HealthStatus {
+	switch {
+	case t.Critical != nil && count > *t.Critical:
+		return StatusCritical
+	case t.Warning != nil && count > *t.Warning:
+		return StatusWarning
+	default:
+		return StatusHealthy
+	}
+}
+
+// merge returns t with the bounds set in override replacing its own.
+func (t Threshold) merge(override Threshold) Threshold {
+	if override.Warning != nil {
+		t.Warning = override.Warning
+	}
+	if override.Critical != nil {
+		t.Critical = override.Critical
+	}
+	return t
+}
+
+func (t Threshold) validate(signal string) error {
+	if t.Warning != nil && t.Critical != nil && *t.Warning > *t.Critical {
+		return fmt.Errorf("%s: warning threshold %d is above critical threshold %d", signal, *t.Warning, *t.Critical)
+	}
+	return nil
+}
+
+// Thresholds holds the threshold for each signal the health policy grades.
+type Thresholds struct {
+	// Restarts is the number of container restarts within the time window
+	Restarts    Threshold `json:"restarts"`
+	PendingPods Threshold `json:"pendingPods"`
+	FailedPods  Threshold `json:"failedPods"`
+	// ContainerReasons is the number of containers reporting a problem
+	// reason such as CrashLoopBackOff or ImagePullBackOff
+	ContainerReasons Threshold `json:"containerReasons"`
+	OOMKills         Threshold `json:"oomKills"`
+	// DegradedWorkloads counts workloads short of replicas, and
+	// UnavailableWorkloads the subset with no replicas available at all
+	DegradedWorkloads    Threshold `json:"degradedWorkloads"`
+	UnavailableWorkloads Threshold `json:"unavailableWorkloads"`
+	StuckRollouts        Threshold `json:"stuckRollouts"`
+	// AutoscalerIssues counts HPAs at maxReplicas, unable to scale or
+	// limited in scaling
+	AutoscalerIssues Threshold `json:"autoscalerIssues"`
+	// DegradedServices counts services with most endpoints not ready, and
+	// UnavailableServices the subset with no ready endpoint at all
+	DegradedServices    Threshold `json:"degradedServices"`
+	UnavailableServices Threshold `json:"unavailableServices"`
+	// RouteIssues counts broken Ingress and HTTPRoute backends and routes
+	// rejected by their Gateway
+	RouteIssues Threshold `json:"routeIssues"`
+	// ExpiringCertificates counts certificates expiring within the
+	// certificate horizon, failing to renew or unreadable, and
+	// ExpiredCertificates the ones already expired
+	ExpiringCertificates Threshold `json:"expiringCertificates"`
+	ExpiredCertificates  Threshold `json:"expiredCertificates"`
+	// WarningEvents counts the occurrences of Warning events within the
+	// time window
+	WarningEvents Threshold `json:"warningEvents"`
+	// StorageIssues counts claims, volumes, attachments and pods with
+	// storage problems
+	StorageIssues Threshold `json:"storageIssues"`
+	// NotReadyNodes counts nodes that are NotReady or Unknown, and
+	// PressureNodes nodes reporting any pressure condition
+	NotReadyNodes Threshold `json:"notReadyNodes"`
+	PressureNodes Threshold `json:"pressureNodes"`
+	// IncompleteSections counts the sections left out of the pulse because
+	// they timed out
+	IncompleteSections Threshold `json:"incompleteSections"`
+	// OffenderRestarts grades the total restarts of each top offender
+	OffenderRestarts Threshold `json:"offenderRestarts"`
+}
+
+func (t Thresholds) merge(override Thresholds) Thresholds {
+	t.Restarts = t.Restarts.merge(override.Restarts)
+	t.PendingPods = t.PendingPods.merge(override.PendingPods)
+	t.FailedPods = t.FailedPods.merge(override.FailedPods)
+	t.ContainerReasons = t.ContainerReasons.merge(override.ContainerReasons)
+	t.OOMKills = t.OOMKills.merge(override.OOMKills)
+	t.DegradedWorkloads = t.DegradedWorkloads.merge(override.DegradedWorkloads)
+	t.UnavailableWorkloads = t.UnavailableWorkloads.merge(override.UnavailableWorkloads)
+	t.StuckRollouts = t.StuckRollouts.merge(override.StuckRollouts)
+	t.AutoscalerIssues = t.AutoscalerIssues.merge(override.AutoscalerIssues)
+	t.DegradedServices = t.DegradedServices.merge(override.DegradedServices)
+	t.UnavailableServices = t.UnavailableServices.merge(override.UnavailableServices)
+	t.RouteIssues = t.RouteIssues.merge(override.RouteIssues)
+	t.ExpiringCertificates = t.ExpiringCertificates.merge(override.ExpiringCertificates)
+	t.ExpiredCertificates = t.ExpiredCertificates.merge(override.ExpiredCertificates)
+	t.WarningEvents = t.WarningEvents.merge(override.WarningEvents)
+	t.StorageIssues = t.StorageIssues.merge(override.StorageIssues)
+	t.NotReadyNodes = t.NotReadyNodes.merge(override.NotReadyNodes)
+	t.PressureNodes = t.PressureNodes.merge(override.PressureNodes)
+	t.IncompleteSections = t.IncompleteSections.merge(override.IncompleteSections)
+	t.OffenderRestarts = t.OffenderRestarts.merge(override.OffenderRestarts)
+	return t
+}
+
+// bySignal indexes the thresholds by signal name.
+func (t Thresholds) bySignal() map[string]Threshold {
+	return map[string]Threshold{
+		SignalRestarts:             t.Restarts,
+		SignalPendingPods:          t.PendingPods,
+		SignalFailedPods:           t.FailedPods,
+		SignalContainerReasons:     t.ContainerReasons,
+		SignalOOMKills:             t.OOMKills,
+		SignalDegradedWorkloads:    t.DegradedWorkloads,
+		SignalUnavailableWorkloads: t.UnavailableWorkloads,
+		SignalStuckRollouts:        t.StuckRollouts,
+		SignalAutoscalerIssues:     t.AutoscalerIssues,
+		SignalDegradedServices:     t.DegradedServices,
+		SignalUnavailableServices:  t.UnavailableServices,
+		SignalRouteIssues:          t.RouteIssues,
+		SignalExpiringCertificates: t.ExpiringCertificates,
+		SignalExpiredCertificates:  t.ExpiredCertificates,
+		SignalWarningEvents:        t.WarningEvents,
+		SignalStorageIssues:        t.StorageIssues,
+		SignalNotReadyNodes:        t.NotReadyNodes,
+		SignalPressureNodes:        t.PressureNodes,
+		SignalIncompleteSections:   t.IncompleteSections,
+		SignalOffenderRestarts:     t.OffenderRestarts,
+	}
+}
+
+func (t Thresholds) validate() error {
+	for signal, threshold := range t.bySignal() {
+		if err := threshold.validate(signal); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HealthPolicy decides how the signals of a pulse map to HEALTHY, WARNING and
+// CRITICAL. Namespaces overrides thresholds for individual namespaces; bounds
+// an override leaves unset fall back to the cluster-wide ones. Node signals
+// and incomplete sections are cluster-scoped and ignore namespace overrides.
+type HealthPolicy struct {
+	Thresholds
+	Namespaces map[string]Thresholds `json:"namespaces,omitempty"`
+	// CertificateHorizon is how far ahead certificates are reported as
+	// expiring
+	CertificateHorizon Duration `json:"certificateHorizon,omitzero"`
+}
+
+// DefaultCertificateHorizon leaves renewals, which cert-manager attempts a
+// third of the lifetime before expiry, time to fail before they are reported.
+const DefaultCertificateHorizon = 14 * 24 * time.Hour
+
+// Duration is a time.Duration written in policy files as a Go duration such
+// as "36h", or as a whole number of days such as "14d".
+type Duration struct {
+	time.Duration
+}
+
+func (d Duration) String() string {
+	if d.Duration > 0 && d.Duration%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", d.Duration/(24*time.Hour))
+	}
+	return d.Duration.String()
+}
+
+// ParseDuration reads a Go duration or a whole number of days.
+func ParseDuration(s string) (Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return Duration{}, fmt.Errorf("invalid duration %q", s)
+		}
+		return Duration{time.Duration(n) * 24 * time.Hour}, nil
+	}
+
+	duration, err := time.ParseDuration(s)
+	if err != nil {
+		return Duration{}, err
+	}
+	return Duration{duration}, nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"14d\" or \"36h\"")
+	}
+	duration, err := ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = duration
+	return nil
+}
+
+// bound returns a pointer to a fresh copy of n, so policies never share
+// bounds that loading a file would then overwrite.
+func bound(n int) *int {
+	return &n
+}
+
+// DefaultHealthPolicy returns the policy used when none is configured.
+func DefaultHealthPolicy() *HealthPolicy {
+	return &HealthPolicy{
+		Thresholds: Thresholds{
+			Restarts:             Threshold{Warning: bound(0), Critical: bound(5)},
+			ContainerReasons:     Threshold{Warning: bound(0)},
+			OOMKills:             Threshold{Warning: bound(0)},
+			DegradedWorkloads:    Threshold{Warning: bound(0)},
+			UnavailableWorkloads: Threshold{Critical: bound(0)},
+			StuckRollouts:        Threshold{Critical: bound(0)},
+			AutoscalerIssues:     Threshold{Warning: bound(0)},
+			DegradedServices:     Threshold{Warning: bound(0)},
+			UnavailableServices:  Threshold{Critical: bound(0)},
+			RouteIssues:          Threshold{Warning: bound(0)},
+			ExpiringCertificates: Threshold{Warning: bound(0)},
+			ExpiredCertificates:  Threshold{Critical: bound(0)},
+			StorageIssues:        Threshold{Warning: bound(0)},
+			NotReadyNodes:        Threshold{Critical: bound(0)},
+			PressureNodes:        Threshold{Warning: bound(0)},
+			IncompleteSections:   Threshold{Warning: bound(0)},
+			OffenderRestarts:     Threshold{Warning: bound(10), Critical: bound(100)},
+		},
+		CertificateHorizon: Duration{DefaultCertificateHorizon},
+	}
+}
+
+// DefaultHealthPolicyPath returns the policy file read when none is given,
+// in the user's config directory.
+func DefaultHealthPolicyPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, "kubectl-pulse", "policy.yaml"), nil
+}
+
+// LoadHealthPolicy reads a YAML or JSON policy file. Signals the file does not
+// mention keep their default thresholds.
+func LoadHealthPolicy(path string) (*HealthPolicy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := DefaultHealthPolicy()
+	if err := yaml.UnmarshalStrict(data, policy); err != nil {
+		return nil, fmt.Errorf("invalid health policy %s: %w", path, err)
+	}
+
+	if err := policy.Thresholds.validate(); err != nil {
+		return nil, fmt.Errorf("invalid health policy %s: %w", path, err)
+	}
+	if policy.CertificateHorizon.Duration < 0 {
+		return nil, fmt.Errorf("invalid health policy %s: certificateHorizon %s is negative", path, policy.CertificateHorizon)
+	}
+	for namespace := range policy.Namespaces {
+		if err := policy.For(namespace).validate(); err != nil {
+			return nil, fmt.Errorf("invalid health policy %s: namespace %s: %w", path, namespace, err)
+		}
+	}
+
+	return policy, nil
+}
+
+// For returns the thresholds that apply to namespace.
+func (p *HealthPolicy) For(namespace string) Thresholds {
+	override, ok := p.Namespaces[namespace]
+	if !ok {
+		return p.Thresholds
+	}
+	return p.Thresholds.merge(override)
+}
+
+// group returns the key namespace is graded under: its own name when it has
+// an override, otherwise "" for the namespaces graded together against the
+// cluster-wide thresholds.
+func (p *HealthPolicy) group(namespace string) string {
+	if _, ok := p.Namespaces[namespace]; ok {
+		return namespace
+	}
+	return ""
+}
@@ -1,53 +1,247 @@
 package pulse
 
-import "k8s.io/client-go/kubernetes"
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
 
 type Service struct {
 	client    *Client
 	analyzer  *Analyzer
-	formatter *Formatter
+	formatter Formatter
+	history   *RestartHistory
 }
 
-func NewService() (*Service, error) {
-	client, err := NewClient()
+func NewService(opts ClientOptions) (*Service, error) {
+	client, err := NewClient(opts)
 	if err != nil {
 		return nil, err
 	}
 
+	// An unreadable history only costs accuracy; start a new one. Without a
+	// cache directory, as in containers without $HOME, it stays in memory.
+	history := NewRestartHistory("")
+	if path, err := DefaultRestartHistoryPath(client.host); err == nil {
+		if history, err = LoadRestartHistory(path); err != nil {
+			history = NewRestartHistory(path)
+		}
+	}
+
 	return &Service{
 		client:    client,
-		analyzer:  NewAnalyzer(),
-		formatter: NewFormatter(),
+		analyzer:  NewAnalyzerWithHistory(history),
+		formatter: NewTextFormatter(),
+		history:   history,
 	}, nil
 }
 
 func NewServiceWithClientset(clientset kubernetes.Interface) (*Service, error) {
+	return NewServiceWithClients(clientset, nil)
+}
+
+// NewServiceWithClients is NewServiceWithClientset with a dynamic client for
+// custom resources such as Gateway API routes.
+func NewServiceWithClients(clientset kubernetes.Interface, dynamicClient dynamic.Interface) (*Service, error) {
 	client := &Client{
 		clientset: clientset,
+		dynamic:   dynamicClient,
 	}
 
 	return &Service{
 		client:    client,
 		analyzer:  NewAnalyzer(),
-		formatter: NewFormatter(),
+		formatter: NewTextFormatter(),
 	}, nil
 }
 
-func (s *Service) GetClusterPulse(timeWindowMinutes int, podAmount int, namespace string) (string, error) {
+// SetOutput selects the registered formatter GetClusterPulse renders with.
+func (s *Service) SetOutput(output string) error {
+	formatter, err := NewFormatter(output)
+	if err != nil {
+		return err
+	}
+	s.formatter = formatter
+	return nil
+}
+
+// SetPolicy selects the health policy the pulse is graded with.
+func (s *Service) SetPolicy(policy *HealthPolicy) {
+	s.analyzer.SetPolicy(policy)
+}
+
+// StartInformers serves subsequent pulses from a shared informer cache instead
+// of listing every resource on each call. See Client.StartInformers.
+func (s *Service) StartInformers(ctx context.Context, namespace string) error {
+	return s.client.StartInformers(ctx, namespace)
+}
+
+func (s *Service) StopInformers() {
+	s.client.StopInformers()
+}
+
+func (s *Service) GetClusterPulse(ctx context.Context, timeWindowMinutes int, podAmount int, namespace string) (string, error) {
+	health, err := s.GetClusterHealth(ctx, timeWindowMinutes, podAmount, namespace)
+	if err != nil {
+		return "", err
+	}
+
+	return s.Format(health)
+}
+
+// Format renders health with the formatter selected by SetOutput.
+func (s *Service) Format(health ClusterHealth) (string, error) {
+	return s.formatter.FormatClusterHealth(health)
+}
+
+// GetClusterHealth runs the analysis without rendering it.
+func (s *Service) GetClusterHealth(ctx context.Context, timeWindowMinutes int, podAmount int, namespace string) (ClusterHealth, error) {
+	health, _, err := s.collectClusterHealth(ctx, timeWindowMinutes, podAmount, namespace)
+	return health, err
+}
+
+// collectClusterHealth runs the analysis and also returns the pods it was
+// based on, for callers that compare successive runs.
+//
+// Pods are required. The remaining sections are optional: a section the
+// caller may not read is left out, and one that times out is named in
+// health.Incomplete so the rest of the pulse is still returned.
+func (s *Service) collectClusterHealth(ctx context.Context, timeWindowMinutes int, podAmount int, namespace string) (ClusterHealth, []PodStatus, error) {
 	var pods []PodStatus
 	var err error
 
 	if namespace != "" {
-		pods, err = s.client.GetPodStatusesInNamespace(namespace)
+		pods, err = s.client.GetPodStatusesInNamespace(ctx, namespace)
 	} else {
-		pods, err = s.client.GetPodStatuses()
+		pods, err = s.client.GetPodStatuses(ctx)
 	}
 
 	if err != nil {
-		return "", err
+		return ClusterHealth{}, nil, err
 	}
 
 	health := s.analyzer.AnalyzeClusterHealth(pods, timeWindowMinutes, podAmount, namespace)
 
-	return s.formatter.FormatClusterHealth(health), nil
+	// The history only sharpens later runs, so failing to persist it is not
+	// worth failing this one over. It is saved last, once the rollouts have
+	// added their generation lags.
+	now := time.Now()
+	s.history.Record(pods, now)
+	defer func() { _ = s.history.Checkpoint(now) }()
+
+	// Nodes are cluster-scoped, so namespace-scoped users may not see them
+	nodes, err := s.client.GetNodeStatuses(ctx)
+	if err == nil {
+		health.Nodes = s.analyzer.AnalyzeNodeHealth(nodes)
+	} else if err := skipSection(&health, SectionNodes, err); err != nil {
+		return ClusterHealth{}, nil, err
+	}
+
+	// Workloads and rollouts are both derived from the Deployments, so they
+	// are read once; when that fails, both sections are left out
+	deployments, deploymentsErr := s.client.GetDeployments(ctx, namespace)
+
+	var workloads []WorkloadStatus
+	err = deploymentsErr
+	if err == nil {
+		workloads, err = s.client.GetWorkloadStatuses(ctx, namespace, deployments)
+	}
+	if err == nil {
+		health.DegradedWorkloads = s.analyzer.AnalyzeWorkloads(workloads)
+	} else if err := skipSection(&health, SectionWorkloads, err); err != nil {
+		return ClusterHealth{}, nil, err
+	}
+
+	var rollouts []RolloutStatus
+	err = deploymentsErr
+	if err == nil {
+		rollouts, err = s.client.GetRolloutStatuses(ctx, namespace, deployments)
+	}
+	if err == nil {
+		health.StuckRollouts = s.analyzer.AnalyzeRollouts(rollouts)
+	} else if err := skipSection(&health, SectionRollouts, err); err != nil {
+		return ClusterHealth{}, nil, err
+	}
+
+	autoscalers, err := s.client.GetAutoscalerStatuses(ctx, namespace)
+	if err == nil {
+		health.Autoscalers = s.analyzer.AnalyzeAutoscalers(autoscalers)
+	} else if err := skipSection(&health, SectionAutoscalers, err); err != nil {
+		return ClusterHealth{}, nil, err
+	}
+
+	services, err := s.client.GetServiceStatuses(ctx, namespace)
+	if err == nil {
+		health.UnhealthyServices = s.analyzer.AnalyzeServices(services, pods)
+
+		// Routes are checked against the services they send traffic to
+		routes, err := s.client.GetRouteStatuses(ctx, namespace)
+		if err == nil {
+			health.RouteIssues = s.analyzer.AnalyzeRoutes(routes, services, namespace)
+		} else if err := skipSection(&health, SectionRoutes, err); err != nil {
+			return ClusterHealth{}, nil, err
+		}
+	} else if err := skipSection(&health, SectionServices, err); err != nil {
+		return ClusterHealth{}, nil, err
+	}
+
+	events, err := s.client.GetEvents(ctx, namespace)
+	if err == nil {
+		health.WarningEvents = s.analyzer.AnalyzeEvents(events, timeWindowMinutes)
+	} else if err := skipSection(&health, SectionEvents, err); err != nil {
+		return ClusterHealth{}, nil, err
+	}
+
+	certificates, err := s.client.GetCertificates(ctx, namespace)
+	if err == nil {
+		health.Certificates = s.analyzer.AnalyzeCertificates(certificates)
+	} else if err := skipSection(&health, SectionCertificates, err); err != nil {
+		return ClusterHealth{}, nil, err
+	}
+
+	// Storage reuses the events for the pods stuck attaching or mounting
+	storage, err := s.client.GetStorageStatus(ctx, namespace)
+	if err == nil {
+		health.StorageIssues = s.analyzer.AnalyzeStorage(storage, health.WarningEvents, namespace)
+	} else if err := skipSection(&health, SectionStorage, err); err != nil {
+		return ClusterHealth{}, nil, err
+	}
+
+	health.Signals = s.analyzer.GradeSignals(health)
+	health.Status = s.analyzer.DetermineStatus(health)
+
+	return health, pods, nil
+}
+
+// skipSection decides whether the pulse can go on without a section that
+// failed to load. It returns err back when the failure should abort the pulse.
+func skipSection(health *ClusterHealth, section string, err error) error {
+	switch {
+	case apierrors.IsForbidden(err):
+		health.Forbidden = append(health.Forbidden, section)
+		return nil
+	case errors.Is(err, context.DeadlineExceeded) || apierrors.IsTimeout(err) || apierrors.IsServerTimeout(err):
+		health.Incomplete = append(health.Incomplete, section)
+		return nil
+	default:
+		return err
+	}
+}
+
+// skipReason tells why a section is missing from health: "timed out",
+// "forbidden", or "" when it was read.
+func skipReason(health ClusterHealth, section string) string {
+	switch {
+	case slices.Contains(health.Incomplete, section):
+		return "timed out"
+	case slices.Contains(health.Forbidden, section):
+		return "forbidden"
+	default:
+		return ""
+	}
 }
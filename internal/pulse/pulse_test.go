@@ -2,13 +2,39 @@ package pulse
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 func TestGetClusterPulseHealthy(t *testing.T) {
@@ -19,7 +45,7 @@ func TestGetClusterPulseHealthy(t *testing.T) {
 		t.Fatalf("Failed to create service: %v", err)
 	}
 
-	result, err := service.GetClusterPulse(15, 30, "")
+	result, err := service.GetClusterPulse(context.Background(), 15, 30, "")
 	if err != nil {
 		t.Fatalf("Failed to get cluster pulse: %v", err)
 	}
@@ -99,7 +125,7 @@ func TestGetClusterPulseWarning(t *testing.T) {
 		t.Fatalf("Failed to create service: %v", err)
 	}
 
-	result, err := service.GetClusterPulse(15, 3, "")
+	result, err := service.GetClusterPulse(context.Background(), 15, 3, "")
 	if err != nil {
 		t.Fatalf("Failed to get cluster pulse: %v", err)
 	}
@@ -185,7 +211,7 @@ func TestGetClusterPulseWithNamespace(t *testing.T) {
 	}
 
 	// Test with kube-system namespace
-	result, err := service.GetClusterPulse(15, 3, "kube-system")
+	result, err := service.GetClusterPulse(context.Background(), 15, 3, "kube-system")
 	if err != nil {
 		t.Fatalf("Failed to get cluster pulse: %v", err)
 	}
@@ -193,7 +219,7 @@ func TestGetClusterPulseWithNamespace(t *testing.T) {
 	t.Logf("kube-system namespace result: %s", result)
 
 	// Test with default namespace
-	result, err = service.GetClusterPulse(15, 3, "default")
+	result, err = service.GetClusterPulse(context.Background(), 15, 3, "default")
 	if err != nil {
 		t.Fatalf("Failed to get cluster pulse: %v", err)
 	}
@@ -201,7 +227,7 @@ func TestGetClusterPulseWithNamespace(t *testing.T) {
 	t.Logf("default namespace result: %s", result)
 
 	// Test with all namespaces (empty string)
-	result, err = service.GetClusterPulse(15, 3, "")
+	result, err = service.GetClusterPulse(context.Background(), 15, 3, "")
 	if err != nil {
 		t.Fatalf("Failed to get cluster pulse: %v", err)
 	}
@@ -315,7 +341,7 @@ func TestPodStatusDistribution(t *testing.T) {
 	}
 
 	// Test all namespaces
-	result, err := service.GetClusterPulse(15, 10, "")
+	result, err := service.GetClusterPulse(context.Background(), 15, 10, "")
 	if err != nil {
 		t.Fatalf("Failed to get cluster pulse: %v", err)
 	}
@@ -326,7 +352,7 @@ func TestPodStatusDistribution(t *testing.T) {
 	}
 
 	// Test specific namespace filtering
-	result, err = service.GetClusterPulse(15, 10, "default")
+	result, err = service.GetClusterPulse(context.Background(), 15, 10, "default")
 	if err != nil {
 		t.Fatalf("Failed to get cluster pulse: %v", err)
 	}
@@ -393,7 +419,7 @@ func TestPodStatusDistributionEdgeCases(t *testing.T) {
 	}
 
 	// Test with no pods
-	result, err := service.GetClusterPulse(15, 10, "")
+	result, err := service.GetClusterPulse(context.Background(), 15, 10, "")
 	if err != nil {
 		t.Fatalf("Failed to get cluster pulse: %v", err)
 	}
@@ -405,3 +431,2231 @@ func TestPodStatusDistributionEdgeCases(t *testing.T) {
 
 	t.Logf("Empty cluster test result: %s", result)
 }
+
+func TestGetClusterPulseJSON(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod-1",
+			Namespace: "default",
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					RestartCount: 2,
+					LastTerminationState: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							FinishedAt: metav1.NewTime(time.Now().Add(-5 * time.Minute)),
+						},
+					},
+				},
+			},
+		},
+	}
+	if _, err := clientset.CoreV1().Pods(pod.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Failed to create fake pod: %v", err)
+	}
+
+	service, err := NewServiceWithClientset(clientset)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	if err := service.SetOutput(OutputJSON); err != nil {
+		t.Fatalf("Failed to set output: %v", err)
+	}
+
+	result, err := service.GetClusterPulse(context.Background(), 15, 3, "")
+	if err != nil {
+		t.Fatalf("Failed to get cluster pulse: %v", err)
+	}
+
+	var report struct {
+		SchemaVersion  string       `json:"schemaVersion"`
+		Status         HealthStatus `json:"status"`
+		RecentRestarts int          `json:"recentRestarts"`
+		TopOffenders   []PodStatus  `json:"topOffenders"`
+	}
+	if err := json.Unmarshal([]byte(result), &report); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, result)
+	}
+
+	if report.SchemaVersion != JSONSchemaVersion {
+		t.Errorf("schemaVersion = %q, want %q", report.SchemaVersion, JSONSchemaVersion)
+	}
+	if report.Status != StatusWarning {
+		t.Errorf("status = %q, want %q", report.Status, StatusWarning)
+	}
+	if report.RecentRestarts != 1 {
+		t.Errorf("recentRestarts = %d, want 1", report.RecentRestarts)
+	}
+	if len(report.TopOffenders) != 1 || report.TopOffenders[0].Name != "pod-1" {
+		t.Errorf("unexpected topOffenders: %+v", report.TopOffenders)
+	}
+
+	if err := service.SetOutput("xml"); err == nil {
+		t.Error("Expected error for unsupported output format")
+	}
+}
+
+func TestFormatterRegistry(t *testing.T) {
+	health := ClusterHealth{
+		Status:         StatusWarning,
+		RecentRestarts: 1,
+		RecentRestartPods: []PodStatus{
+			{Name: "pod-1", Namespace: "default", Status: "Running", Restarts: 2, LastRestart: time.Now()},
+		},
+		TopOffenders: []PodStatus{
+			{Name: "pod-1", Namespace: "default", Status: "Running", Restarts: 2},
+		},
+		PodStatusDistribution: PodStatusDistribution{Running: 1, Total: 1},
+		TimeWindow:            15,
+	}
+
+	expected := map[string]string{
+		OutputText:     "⚠️ WARNING - Cluster Pulse",
+		OutputJSON:     `"schemaVersion": "pulse/v2"`,
+		OutputYAML:     "schemaVersion: pulse/v2",
+		OutputMarkdown: "| default | pod-1 | 2 |",
+		OutputNagios:   "PULSE WARNING - ",
+	}
+
+	for _, name := range FormatterNames() {
+		formatter, err := NewFormatter(name)
+		if err != nil {
+			t.Fatalf("NewFormatter(%q) returned error: %v", name, err)
+		}
+
+		result, err := formatter.FormatClusterHealth(health)
+		if err != nil {
+			t.Fatalf("%s formatter returned error: %v", name, err)
+		}
+
+		if want, ok := expected[name]; ok && !strings.Contains(result, want) {
+			t.Errorf("%s output does not contain %q:\n%s", name, want, result)
+		}
+	}
+
+	if _, err := NewFormatter("xml"); err == nil {
+		t.Error("Expected error for unregistered formatter")
+	}
+}
+
+func TestClientOptionsRESTConfig(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	err := os.WriteFile(kubeconfig, []byte(`apiVersion: v1
+kind: Config
+current-context: dev
+clusters:
+- name: dev
+  cluster:
+    server: https://dev.example.com
+- name: prod
+  cluster:
+    server: https://prod.example.com
+users:
+- name: admin
+  user:
+    token: secret
+contexts:
+- name: dev
+  context:
+    cluster: dev
+    user: admin
+- name: prod
+  context:
+    cluster: prod
+    user: admin
+`), 0o600)
+	if err != nil {
+		t.Fatalf("Failed to write kubeconfig: %v", err)
+	}
+
+	config, err := ClientOptions{Kubeconfig: kubeconfig}.RESTConfig()
+	if err != nil {
+		t.Fatalf("Failed to build config: %v", err)
+	}
+	if config.Host != "https://dev.example.com" {
+		t.Errorf("Host = %q, want current-context cluster", config.Host)
+	}
+
+	config, err = ClientOptions{
+		Kubeconfig:     kubeconfig,
+		Context:        "prod",
+		Impersonate:    "jane",
+		RequestTimeout: "5s",
+	}.RESTConfig()
+	if err != nil {
+		t.Fatalf("Failed to build config: %v", err)
+	}
+	if config.Host != "https://prod.example.com" {
+		t.Errorf("Host = %q, want prod cluster", config.Host)
+	}
+	if config.Impersonate.UserName != "jane" {
+		t.Errorf("Impersonate.UserName = %q, want jane", config.Impersonate.UserName)
+	}
+	if config.Timeout != 5*time.Second {
+		t.Errorf("Timeout = %v, want 5s", config.Timeout)
+	}
+
+	config, err = ClientOptions{Kubeconfig: kubeconfig, Cluster: "prod"}.RESTConfig()
+	if err != nil {
+		t.Fatalf("Failed to build config: %v", err)
+	}
+	if config.Host != "https://prod.example.com" {
+		t.Errorf("Host = %q, want --cluster override", config.Host)
+	}
+}
+
+func TestGetFleetPulse(t *testing.T) {
+	healthy := fake.NewSimpleClientset()
+
+	restarting := fake.NewSimpleClientset()
+	for i := range 6 {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("pod-%d", i),
+				Namespace: "default",
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						RestartCount: 1,
+						LastTerminationState: corev1.ContainerState{
+							Terminated: &corev1.ContainerStateTerminated{
+								FinishedAt: metav1.NewTime(time.Now().Add(-time.Minute)),
+							},
+						},
+					},
+				},
+			},
+		}
+		if _, err := restarting.CoreV1().Pods(pod.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("Failed to create fake pod: %v", err)
+		}
+	}
+
+	unreachable := fake.NewSimpleClientset()
+	unreachable.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, fmt.Errorf("connection refused")
+	})
+
+	services := map[string]*Service{}
+	for name, clientset := range map[string]*fake.Clientset{"dev": healthy, "prod": restarting, "broken": unreachable} {
+		service, err := NewServiceWithClientset(clientset)
+		if err != nil {
+			t.Fatalf("Failed to create service: %v", err)
+		}
+		services[name] = service
+	}
+
+	fleet := NewFleetWithServices(services).GetFleetHealth(context.Background(), 15, 3, "")
+
+	if fleet.Status != StatusCritical {
+		t.Errorf("fleet status = %q, want %q", fleet.Status, StatusCritical)
+	}
+	if len(fleet.Clusters) != 3 {
+		t.Fatalf("got %d clusters, want 3", len(fleet.Clusters))
+	}
+
+	byContext := map[string]ClusterResult{}
+	for _, cluster := range fleet.Clusters {
+		byContext[cluster.Context] = cluster
+	}
+	if byContext["broken"].Error == "" || byContext["broken"].Health != nil {
+		t.Errorf("expected broken context to report an error, got %+v", byContext["broken"])
+	}
+	if byContext["dev"].Health == nil || byContext["dev"].Health.Status != StatusHealthy {
+		t.Errorf("expected dev to be healthy, got %+v", byContext["dev"])
+	}
+	if byContext["prod"].Health == nil || byContext["prod"].Health.Status != StatusCritical {
+		t.Errorf("expected prod to be critical, got %+v", byContext["prod"])
+	}
+
+	result, err := NewFleetWithServices(services).GetFleetPulse(context.Background(), 15, 3, "")
+	if err != nil {
+		t.Fatalf("Failed to get fleet pulse: %v", err)
+	}
+	if !strings.Contains(result, "Fleet Pulse (3 clusters)") || !strings.Contains(result, "connection refused") {
+		t.Errorf("unexpected fleet output:\n%s", result)
+	}
+}
+
+func TestGetClusterPulseNodeHealth(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+
+	fakeNodes := []corev1.Node{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-ready"},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{
+					{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-cordoned"},
+			Spec:       corev1.NodeSpec{Unschedulable: true},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{
+					{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+					{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionTrue},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-down"},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{
+					{Type: corev1.NodeReady, Status: corev1.ConditionFalse},
+				},
+			},
+		},
+	}
+
+	for _, node := range fakeNodes {
+		_, err := clientset.CoreV1().Nodes().Create(context.TODO(), &node, metav1.CreateOptions{})
+		if err != nil {
+			t.Fatalf("Failed to create fake node: %v", err)
+		}
+	}
+
+	service, err := NewServiceWithClientset(clientset)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	health, err := service.GetClusterHealth(context.Background(), 15, 3, "")
+	if err != nil {
+		t.Fatalf("Failed to get cluster health: %v", err)
+	}
+
+	if health.Nodes == nil {
+		t.Fatal("Expected node health section")
+	}
+	if health.Nodes.Ready != 2 || health.Nodes.NotReady != 1 || health.Nodes.SchedulingDisabled != 1 {
+		t.Errorf("unexpected node counts: %+v", health.Nodes)
+	}
+	if len(health.Nodes.MemoryPressure) != 1 || health.Nodes.MemoryPressure[0] != "node-cordoned" {
+		t.Errorf("MemoryPressure = %v, want [node-cordoned]", health.Nodes.MemoryPressure)
+	}
+	if health.Status != StatusCritical {
+		t.Errorf("status = %q, want %q with a NotReady node", health.Status, StatusCritical)
+	}
+
+	result, err := service.GetClusterPulse(context.Background(), 15, 3, "")
+	if err != nil {
+		t.Fatalf("Failed to get cluster pulse: %v", err)
+	}
+	if !strings.Contains(result, "🔴 NotReady: node-down") {
+		t.Errorf("Expected NotReady node in output:\n%s", result)
+	}
+
+	// Namespace-scoped users cannot list nodes; the pulse should still succeed
+	clientset.PrependReactor("list", "nodes", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(corev1.Resource("nodes"), "", fmt.Errorf("forbidden"))
+	})
+
+	health, err = service.GetClusterHealth(context.Background(), 15, 3, "")
+	if err != nil {
+		t.Fatalf("Expected forbidden node list to be tolerated, got: %v", err)
+	}
+	if health.Nodes != nil || health.Status != StatusHealthy {
+		t.Errorf("unexpected health without node access: %+v", health)
+	}
+	result, err = service.GetClusterPulse(context.Background(), 15, 3, "")
+	if err != nil {
+		t.Fatalf("Failed to get cluster pulse: %v", err)
+	}
+	if !strings.Contains(result, "Nodes: not permitted to list nodes") || !strings.Contains(result, "🔒 Not checked: nodes not permitted") {
+		t.Errorf("Expected forbidden nodes in output:\n%s", result)
+	}
+
+	// Nodes cut off by the timeout are not mistaken for forbidden ones
+	clientset.PrependReactor("list", "nodes", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewTimeoutError("request timed out", 1)
+	})
+	result, err = service.GetClusterPulse(context.Background(), 15, 3, "")
+	if err != nil {
+		t.Fatalf("Failed to get cluster pulse: %v", err)
+	}
+	if !strings.Contains(result, "⏳ Nodes: not checked (timed out)") || strings.Contains(result, "not permitted") {
+		t.Errorf("Expected timed out nodes in output:\n%s", result)
+	}
+}
+
+func TestGetClusterPulseDegradedWorkloads(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+
+	replicas := int32(5)
+	deployments := []appsv1.Deployment{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status:     appsv1.DeploymentStatus{ReadyReplicas: 1, AvailableReplicas: 1},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status:     appsv1.DeploymentStatus{ReadyReplicas: 5, AvailableReplicas: 5},
+		},
+	}
+	for _, deployment := range deployments {
+		if _, err := clientset.AppsV1().Deployments(deployment.Namespace).Create(context.TODO(), &deployment, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("Failed to create fake deployment: %v", err)
+		}
+	}
+
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "data"},
+		Status:     appsv1.StatefulSetStatus{ReadyReplicas: 0, AvailableReplicas: 0},
+	}
+	if _, err := clientset.AppsV1().StatefulSets(statefulSet.Namespace).Create(context.TODO(), statefulSet, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Failed to create fake statefulset: %v", err)
+	}
+
+	daemonSet := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "kube-system"},
+		Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, NumberReady: 3, NumberAvailable: 3},
+	}
+	if _, err := clientset.AppsV1().DaemonSets(daemonSet.Namespace).Create(context.TODO(), daemonSet, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Failed to create fake daemonset: %v", err)
+	}
+
+	service, err := NewServiceWithClientset(clientset)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	health, err := service.GetClusterHealth(context.Background(), 15, 3, "")
+	if err != nil {
+		t.Fatalf("Failed to get cluster health: %v", err)
+	}
+
+	if len(health.DegradedWorkloads) != 2 {
+		t.Fatalf("got %d degraded workloads, want 2: %+v", len(health.DegradedWorkloads), health.DegradedWorkloads)
+	}
+	if got := health.DegradedWorkloads[0]; got.Kind != "StatefulSet" || got.Name != "db" {
+		t.Errorf("first degraded workload = %+v, want data/db StatefulSet", got)
+	}
+	if got := health.DegradedWorkloads[1]; got.Kind != "Deployment" || got.Name != "api" || got.Ready != 1 || got.Desired != 5 {
+		t.Errorf("second degraded workload = %+v, want default/api Deployment 1/5", got)
+	}
+	if health.Status != StatusCritical {
+		t.Errorf("status = %q, want %q with an unavailable statefulset", health.Status, StatusCritical)
+	}
+
+	result, err := service.GetClusterPulse(context.Background(), 15, 3, "default")
+	if err != nil {
+		t.Fatalf("Failed to get cluster pulse: %v", err)
+	}
+	if !strings.Contains(result, "default/api Deployment (1/5 ready, 1 available)") || strings.Contains(result, "data/db") {
+		t.Errorf("unexpected degraded workloads in namespaced output:\n%s", result)
+	}
+}
+
+func TestGetClusterPulseStuckRollouts(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+
+	replicas := int32(3)
+	deployments := []appsv1.Deployment{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "deadline",
+				Namespace:   "default",
+				Generation:  2,
+				Annotations: map[string]string{"deployment.kubernetes.io/revision": "4"},
+			},
+			Spec: appsv1.DeploymentSpec{Replicas: &replicas},
+			Status: appsv1.DeploymentStatus{
+				ObservedGeneration: 2,
+				Conditions: []appsv1.DeploymentCondition{
+					{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded", Message: `ReplicaSet "deadline-abc" has timed out progressing.`},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "slow",
+				Namespace:   "default",
+				Generation:  3,
+				Annotations: map[string]string{"deployment.kubernetes.io/revision": "7"},
+			},
+			Spec: appsv1.DeploymentSpec{Replicas: &replicas},
+			Status: appsv1.DeploymentStatus{
+				ObservedGeneration: 3,
+				Conditions: []appsv1.DeploymentCondition{
+					{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionTrue, Reason: "ReplicaSetUpdated"},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "done",
+				Namespace:   "default",
+				Generation:  1,
+				Annotations: map[string]string{"deployment.kubernetes.io/revision": "1"},
+			},
+			Spec: appsv1.DeploymentSpec{Replicas: &replicas},
+			Status: appsv1.DeploymentStatus{
+				ObservedGeneration: 1,
+				ReadyReplicas:      3,
+				AvailableReplicas:  3,
+				Conditions: []appsv1.DeploymentCondition{
+					{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionTrue, Reason: "NewReplicaSetAvailable"},
+				},
+			},
+		},
+	}
+	for _, deployment := range deployments {
+		if _, err := clientset.AppsV1().Deployments(deployment.Namespace).Create(context.TODO(), &deployment, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("Failed to create fake deployment: %v", err)
+		}
+	}
+
+	controller := true
+	replicaSet := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "slow-7d9f",
+			Namespace:         "default",
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-30 * time.Minute)),
+			Annotations:       map[string]string{"deployment.kubernetes.io/revision": "7"},
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "apps/v1", Kind: "Deployment", Name: "slow", Controller: &controller},
+			},
+		},
+		Spec:   appsv1.ReplicaSetSpec{Replicas: &replicas},
+		Status: appsv1.ReplicaSetStatus{AvailableReplicas: 1},
+	}
+	if _, err := clientset.AppsV1().ReplicaSets(replicaSet.Namespace).Create(context.TODO(), replicaSet, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Failed to create fake replicaset: %v", err)
+	}
+
+	// Workloads and rollouts share a single read of the Deployments
+	var deploymentLists int
+	clientset.PrependReactor("list", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		deploymentLists++
+		return false, nil, nil
+	})
+
+	service, err := NewServiceWithClientset(clientset)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	health, err := service.GetClusterHealth(context.Background(), 15, 3, "")
+	if err != nil {
+		t.Fatalf("Failed to get cluster health: %v", err)
+	}
+	if deploymentLists != 1 {
+		t.Errorf("expected Deployments to be listed once, got %d", deploymentLists)
+	}
+
+	if len(health.StuckRollouts) != 2 {
+		t.Fatalf("got %d stuck rollouts, want 2: %+v", len(health.StuckRollouts), health.StuckRollouts)
+	}
+	if got := health.StuckRollouts[0]; got.Name != "deadline" || got.Reason != "ProgressDeadlineExceeded" || got.Revision != "4" {
+		t.Errorf("unexpected first stuck rollout: %+v", got)
+	}
+	if got := health.StuckRollouts[1]; got.Name != "slow" || got.Reason != "ReplicaSetUnavailable" || got.ReplicaSet != "slow-7d9f" {
+		t.Errorf("unexpected second stuck rollout: %+v", got)
+	}
+	if health.Status != StatusCritical {
+		t.Errorf("status = %q, want %q with stuck rollouts", health.Status, StatusCritical)
+	}
+
+	// A generation the controller has yet to observe is only reported once
+	// the lag outlasts the grace period
+	analyzer := NewAnalyzer()
+	lagging := []RolloutStatus{{Name: "lagging", Namespace: "default", Generation: 5, ObservedGeneration: 4}}
+	if stuck := analyzer.AnalyzeRollouts(lagging); len(stuck) != 0 {
+		t.Errorf("expected a fresh generation lag to be ignored, got %+v", stuck)
+	}
+	analyzer.history.GenerationLags["default/lagging"] = GenerationLag{Generation: 5, Since: time.Now().Add(-2 * rolloutLagGracePeriod)}
+	if stuck := analyzer.AnalyzeRollouts(lagging); len(stuck) != 1 || stuck[0].Reason != "ObservedGenerationLag" {
+		t.Errorf("expected a persistent generation lag to be reported, got %+v", stuck)
+	}
+	lagging[0].Generation = 6
+	if stuck := analyzer.AnalyzeRollouts(lagging); len(stuck) != 0 {
+		t.Errorf("expected a new generation to restart the grace period, got %+v", stuck)
+	}
+
+	// Lags are saved with the history, so a later one-shot run reports them
+	path := filepath.Join(t.TempDir(), "restarts.json")
+	history := NewRestartHistory(path)
+	history.TrackGenerationLags(lagging, time.Now().Add(-2*rolloutLagGracePeriod))
+	if err := history.Save(); err != nil {
+		t.Fatalf("Failed to save history: %v", err)
+	}
+	loaded, err := LoadRestartHistory(path)
+	if err != nil {
+		t.Fatalf("Failed to load history: %v", err)
+	}
+	if stuck := NewAnalyzerWithHistory(loaded).AnalyzeRollouts(lagging); len(stuck) != 1 || stuck[0].Reason != "ObservedGenerationLag" {
+		t.Errorf("expected a lag first seen by an earlier run to be reported, got %+v", stuck)
+	}
+}
+
+func TestGetClusterPulseContainerReasons(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+
+	fakePods := []corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "crashing", Namespace: "default"},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name:         "app",
+						RestartCount: 12,
+						State: corev1.ContainerState{
+							Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+						},
+						LastTerminationState: corev1.ContainerState{
+							Terminated: &corev1.ContainerStateTerminated{
+								Reason:     "OOMKilled",
+								FinishedAt: metav1.NewTime(time.Now().Add(-3 * time.Minute)),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "bad-image", Namespace: "default"},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				InitContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name: "init",
+						State: corev1.ContainerState{
+							Terminated: &corev1.ContainerStateTerminated{
+								Reason:     "Completed",
+								FinishedAt: metav1.NewTime(time.Now().Add(-time.Minute)),
+							},
+						},
+					},
+				},
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name: "app",
+						State: corev1.ContainerState{
+							Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"},
+						},
+					},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "old-crash", Namespace: "default"},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name:         "app",
+						RestartCount: 1,
+						LastTerminationState: corev1.ContainerState{
+							Terminated: &corev1.ContainerStateTerminated{
+								Reason:     "Error",
+								FinishedAt: metav1.NewTime(time.Now().Add(-2 * time.Hour)),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, pod := range fakePods {
+		if _, err := clientset.CoreV1().Pods(pod.Namespace).Create(context.TODO(), &pod, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("Failed to create fake pod: %v", err)
+		}
+	}
+
+	service, err := NewServiceWithClientset(clientset)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	health, err := service.GetClusterHealth(context.Background(), 15, 3, "")
+	if err != nil {
+		t.Fatalf("Failed to get cluster health: %v", err)
+	}
+
+	expected := map[string]int{"CrashLoopBackOff": 1, "ImagePullBackOff": 1, "OOMKilled": 1}
+	if len(health.ContainerReasons) != len(expected) {
+		t.Errorf("ContainerReasons = %v, want %v", health.ContainerReasons, expected)
+	}
+	for reason, count := range expected {
+		if health.ContainerReasons[reason] != count {
+			t.Errorf("ContainerReasons[%q] = %d, want %d", reason, health.ContainerReasons[reason], count)
+		}
+	}
+	// The crashing container reports two reasons but is one container.
+	if got := health.NamespacePods["default"].ContainerReasons; got != 2 {
+		t.Errorf("expected 2 containers with problems, got %d", got)
+	}
+
+	result, err := service.GetClusterPulse(context.Background(), 15, 3, "")
+	if err != nil {
+		t.Fatalf("Failed to get cluster pulse: %v", err)
+	}
+	if !strings.Contains(result, "🩺 Container reasons: CrashLoopBackOff 1, ImagePullBackOff 1, OOMKilled 1") {
+		t.Errorf("Expected container reason breakdown in output:\n%s", result)
+	}
+}
+
+func TestGetClusterPulseOOMKills(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-7f9c", Namespace: "default"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "app",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("128Mi")},
+						Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+					},
+				},
+				{Name: "sidecar"},
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:         "app",
+					RestartCount: 4,
+					LastTerminationState: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							Reason:     "OOMKilled",
+							ExitCode:   137,
+							FinishedAt: metav1.NewTime(time.Now().Add(-2 * time.Minute)),
+						},
+					},
+				},
+				{
+					Name:         "sidecar",
+					RestartCount: 1,
+					LastTerminationState: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							Reason:     "OOMKilled",
+							FinishedAt: metav1.NewTime(time.Now().Add(-3 * time.Hour)),
+						},
+					},
+				},
+			},
+		},
+	}
+	if _, err := clientset.CoreV1().Pods(pod.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Failed to create fake pod: %v", err)
+	}
+
+	service, err := NewServiceWithClientset(clientset)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	health, err := service.GetClusterHealth(context.Background(), 15, 3, "")
+	if err != nil {
+		t.Fatalf("Failed to get cluster health: %v", err)
+	}
+
+	if len(health.OOMKills) != 1 {
+		t.Fatalf("got %d OOM kills, want 1 within the window: %+v", len(health.OOMKills), health.OOMKills)
+	}
+	kill := health.OOMKills[0]
+	if kill.Container != "app" || kill.MemoryRequest != "128Mi" || kill.MemoryLimit != "256Mi" || kill.Restarts != 4 || kill.Kills != 1 {
+		t.Errorf("unexpected OOM kill: %+v", kill)
+	}
+
+	result, err := service.GetClusterPulse(context.Background(), 15, 3, "")
+	if err != nil {
+		t.Fatalf("Failed to get cluster pulse: %v", err)
+	}
+	if !strings.Contains(result, "default/api-7f9c [app] limit 256Mi, request 128Mi (~1 OOM kills in window, 4 restarts total)") {
+		t.Errorf("Expected OOM kill in output:\n%s", result)
+	}
+
+	// With a sample from before the window, the kills in it are estimated
+	// from the pod's restarts since
+	now := time.Now()
+	history := NewRestartHistory("")
+	history.Samples["api"] = []RestartSample{{Time: now.Add(-20 * time.Minute), Restarts: 2}}
+	pods := []PodStatus{{
+		UID: "api", Name: "api-7f9c", Namespace: "default", Created: now.Add(-2 * time.Hour), Restarts: 5, LastRestart: now.Add(-2 * time.Minute),
+		OOMKills: []OOMKill{{Namespace: "default", Pod: "api-7f9c", Container: "app", Restarts: 4, KilledAt: now.Add(-2 * time.Minute)}},
+	}}
+	health = NewAnalyzerWithHistory(history).AnalyzeClusterHealth(pods, 15, 3, "")
+	if len(health.OOMKills) != 1 || health.OOMKills[0].Kills != 3 {
+		t.Errorf("expected 3 OOM kills estimated in the window, got %+v", health.OOMKills)
+	}
+}
+
+func TestGetClusterPulseContainerAttribution(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default"},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			InitContainerStatuses: []corev1.ContainerStatus{
+				{Name: "migrate", Image: "migrate:1.0"},
+			},
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "app", Image: "web:2.3"},
+				{
+					Name:         "proxy",
+					Image:        "envoy:1.30",
+					RestartCount: 9,
+					LastTerminationState: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{
+							ExitCode:   1,
+							Reason:     "Error",
+							FinishedAt: metav1.NewTime(time.Now().Add(-time.Minute)),
+						},
+					},
+				},
+			},
+			EphemeralContainerStatuses: []corev1.ContainerStatus{
+				{Name: "debugger", Image: "busybox"},
+			},
+		},
+	}
+	if _, err := clientset.CoreV1().Pods(pod.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Failed to create fake pod: %v", err)
+	}
+
+	service, err := NewServiceWithClientset(clientset)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	health, err := service.GetClusterHealth(context.Background(), 15, 3, "")
+	if err != nil {
+		t.Fatalf("Failed to get cluster health: %v", err)
+	}
+
+	if len(health.TopOffenders) != 1 {
+		t.Fatalf("got %d top offenders, want 1", len(health.TopOffenders))
+	}
+	containers := health.TopOffenders[0].Containers
+	if len(containers) != 4 {
+		t.Fatalf("got %d containers, want 4: %+v", len(containers), containers)
+	}
+	if !containers[0].Init || containers[0].Name != "migrate" {
+		t.Errorf("expected init container first, got %+v", containers[0])
+	}
+	if !containers[3].Ephemeral || containers[3].Name != "debugger" {
+		t.Errorf("expected ephemeral container last, got %+v", containers[3])
+	}
+	if proxy := containers[2]; proxy.Restarts != 9 || proxy.ExitCode != 1 || proxy.Reason != "Error" || proxy.Image != "envoy:1.30" {
+		t.Errorf("unexpected proxy container: %+v", proxy)
+	}
+
+	result, err := service.GetClusterPulse(context.Background(), 15, 3, "")
+	if err != nil {
+		t.Fatalf("Failed to get cluster pulse: %v", err)
+	}
+	if !strings.Contains(result, "└ proxy: 9 restarts, last exit 1 Error") || strings.Contains(result, "└ app") {
+		t.Errorf("Expected only the restarting container in the drill-down:\n%s", result)
+	}
+}
+
+func TestRestartRateEstimation(t *testing.T) {
+	now := time.Now()
+	path := filepath.Join(t.TempDir(), "restarts.json")
+
+	history := NewRestartHistory(path)
+	history.Samples["flapping"] = []RestartSample{
+		{Time: now.Add(-40 * time.Minute), Restarts: 2},
+		{Time: now.Add(-20 * time.Minute), Restarts: 10},
+		{Time: now.Add(-10 * time.Minute), Restarts: 30},
+	}
+	history.Samples["partial"] = []RestartSample{
+		{Time: now.Add(-5 * time.Minute), Restarts: 3},
+	}
+	// Restarts since a sample hours before the window are not recent
+	history.Samples["stale"] = []RestartSample{
+		{Time: now.Add(-4 * time.Hour), Restarts: 0},
+	}
+	if err := history.Save(); err != nil {
+		t.Fatalf("Failed to save history: %v", err)
+	}
+
+	loaded, err := LoadRestartHistory(path)
+	if err != nil {
+		t.Fatalf("Failed to load history: %v", err)
+	}
+
+	pods := []PodStatus{
+		{UID: "flapping", Name: "flapping", Namespace: "default", Created: now.Add(-2 * time.Hour), Restarts: 50, LastRestart: now.Add(-time.Minute)},
+		{UID: "partial", Name: "partial", Namespace: "default", Created: now.Add(-2 * time.Hour), Restarts: 5, LastRestart: now.Add(-time.Minute)},
+		{UID: "young", Name: "young", Namespace: "default", Created: now.Add(-10 * time.Minute), Restarts: 7, LastRestart: now.Add(-time.Minute)},
+		{UID: "stable", Name: "stable", Namespace: "default", Created: now.Add(-2 * time.Hour), Restarts: 4, LastRestart: now.Add(-time.Hour)},
+		{UID: "stale", Name: "stale", Namespace: "default", Created: now.Add(-5 * time.Hour), Restarts: 100, LastRestart: now.Add(-time.Hour)},
+	}
+
+	health := NewAnalyzerWithHistory(loaded).AnalyzeClusterHealth(pods, 15, 3, "")
+
+	expected := map[string]int32{"flapping": 40, "young": 7, "partial": 2}
+	if len(health.RecentRestartPods) != len(expected) {
+		t.Fatalf("got %d recent restart pods, want %d: %+v", len(health.RecentRestartPods), len(expected), health.RecentRestartPods)
+	}
+	for _, pod := range health.RecentRestartPods {
+		if pod.RecentRestarts != expected[pod.Name] {
+			t.Errorf("%s RecentRestarts = %d, want %d", pod.Name, pod.RecentRestarts, expected[pod.Name])
+		}
+	}
+	if health.RecentRestartPods[0].Name != "flapping" {
+		t.Errorf("expected pods ordered by recent restarts, got %s first", health.RecentRestartPods[0].Name)
+	}
+	if health.RecentRestarts != 49 {
+		t.Errorf("RecentRestarts = %d, want 49", health.RecentRestarts)
+	}
+	if !health.RestartBaseline.Equal(history.Samples["flapping"][1].Time) {
+		t.Errorf("RestartBaseline = %v, want the oldest sample used", health.RestartBaseline)
+	}
+	if health.Status != StatusCritical {
+		t.Errorf("status = %q, want %q", health.Status, StatusCritical)
+	}
+
+	// Recording prunes samples beyond the retention but keeps one baseline
+	loaded.Samples["gone"] = []RestartSample{{Time: now.Add(-48 * time.Hour), Restarts: 1}}
+	loaded.Samples["flapping"] = append([]RestartSample{{Time: now.Add(-30 * time.Hour)}, {Time: now.Add(-25 * time.Hour)}}, loaded.Samples["flapping"]...)
+	loaded.Record(pods, now)
+
+	if _, ok := loaded.Samples["gone"]; ok {
+		t.Error("expected history of a pod not seen within the retention to be dropped")
+	}
+	if got := loaded.Samples["flapping"]; len(got) != 5 || !got[0].Time.Equal(now.Add(-25*time.Hour)) {
+		t.Errorf("unexpected pruned samples: %+v", got)
+	}
+
+	// Refreshes within the sample spacing, as in watch mode, only update the
+	// latest sample
+	loaded.Record(pods, now.Add(5*time.Second))
+	loaded.Record(pods, now.Add(10*time.Second))
+	if got := loaded.Samples["flapping"]; len(got) != 6 || !got[5].Time.Equal(now.Add(10*time.Second)) {
+		t.Errorf("unexpected samples after refreshes: %+v", got)
+	}
+}
+
+func TestRestartHistoryAcrossRuns(t *testing.T) {
+	t0 := time.Now().Add(-3 * time.Hour)
+	path := filepath.Join(t.TempDir(), "restarts.json")
+	pods := []PodStatus{{UID: "api", Name: "api", Namespace: "default", Created: t0.Add(-time.Hour), Restarts: 100}}
+
+	// Each one-shot run is a new process loading what the last one saved
+	for _, at := range []time.Time{t0, t0.Add(10 * time.Second), t0.Add(time.Hour), t0.Add(2 * time.Hour)} {
+		history, err := LoadRestartHistory(path)
+		if err != nil {
+			t.Fatalf("Failed to load history: %v", err)
+		}
+		history.Record(pods, at)
+		if err := history.Checkpoint(at); err != nil {
+			t.Fatalf("Failed to save history: %v", err)
+		}
+	}
+
+	history, err := LoadRestartHistory(path)
+	if err != nil {
+		t.Fatalf("Failed to load history: %v", err)
+	}
+	if got := history.Samples["api"]; len(got) == 0 || !got[len(got)-1].Time.Equal(t0.Add(2*time.Hour)) {
+		t.Fatalf("expected the last run to be saved, got %+v", got)
+	}
+	if health := NewAnalyzerWithHistory(history).AnalyzeClusterHealth(pods, 15, 3, ""); health.RecentRestarts != 0 {
+		t.Errorf("expected no recent restarts against an hour old sample, got %d", health.RecentRestarts)
+	}
+
+	// Within a process, refreshes inside the sample spacing are not saved
+	at := t0.Add(2*time.Hour + 5*time.Second)
+	history.Record(pods, at)
+	if err := history.Checkpoint(at); err != nil {
+		t.Fatalf("Failed to save history: %v", err)
+	}
+	history.Record(pods, at.Add(5*time.Second))
+	if err := history.Checkpoint(at.Add(5 * time.Second)); err != nil {
+		t.Fatalf("Failed to save history: %v", err)
+	}
+	saved, err := LoadRestartHistory(path)
+	if err != nil {
+		t.Fatalf("Failed to load history: %v", err)
+	}
+	if got := saved.Samples["api"]; !got[len(got)-1].Time.Equal(at) {
+		t.Errorf("expected the refresh within the spacing not to be saved, got %+v", got)
+	}
+}
+
+func TestRestartHistoryStaysSmall(t *testing.T) {
+	start := time.Now().Add(-restartHistoryRetention)
+	history := NewRestartHistory("")
+
+	// A day of watch mode, refreshing every 5s, for a stable pod and one
+	// restarting every minute
+	var restarts int32
+	for at := start; !at.After(start.Add(restartHistoryRetention)); at = at.Add(5 * time.Second) {
+		if at.Sub(start)%time.Minute == 0 {
+			restarts++
+		}
+		history.Record([]PodStatus{
+			{UID: "stable", Restarts: 3},
+			{UID: "flapping", Restarts: restarts},
+		}, at)
+	}
+	now := start.Add(restartHistoryRetention)
+
+	if got := history.Samples["stable"]; len(got) != 2 {
+		t.Errorf("expected an unchanged count to be kept as 2 samples, got %d", len(got))
+	}
+	if got := history.Samples["flapping"]; len(got) >= restartHistoryMaxSamples {
+		t.Errorf("expected old samples to be thinned below the cap, got %d", len(got))
+	}
+
+	for _, window := range []time.Duration{5 * time.Minute, 15 * time.Minute, time.Hour, 6 * time.Hour, 23 * time.Hour} {
+		for _, uid := range []string{"stable", "flapping"} {
+			if _, covered, _ := history.Baseline(uid, window, now); !covered {
+				t.Errorf("expected the thinned history of %s to cover a %s window", uid, window)
+			}
+		}
+	}
+}
+
+func TestWatcherReportsChanges(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+
+	newPod := func(name string, phase corev1.PodPhase, restarts int32) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Status: corev1.PodStatus{
+				Phase:             phase,
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "app", RestartCount: restarts}},
+			},
+		}
+	}
+
+	for _, pod := range []*corev1.Pod{
+		newPod("stable", corev1.PodRunning, 0),
+		newPod("starting", corev1.PodPending, 0),
+		newPod("flapping", corev1.PodRunning, 1),
+		newPod("doomed", corev1.PodRunning, 0),
+	} {
+		if _, err := clientset.CoreV1().Pods(pod.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("Failed to create fake pod: %v", err)
+		}
+	}
+
+	service, err := NewServiceWithClientset(clientset)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+	watcher := NewWatcher(service)
+
+	result, err := watcher.Next(context.Background(), 15, 3, "")
+	if err != nil {
+		t.Fatalf("Failed to render first frame: %v", err)
+	}
+	if strings.Contains(result, "Changes since last refresh") {
+		t.Errorf("First frame should not report changes:\n%s", result)
+	}
+
+	pods := clientset.CoreV1().Pods("default")
+	if _, err := pods.Update(context.TODO(), newPod("starting", corev1.PodRunning, 0), metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Failed to update pod: %v", err)
+	}
+	if _, err := pods.Update(context.TODO(), newPod("flapping", corev1.PodRunning, 4), metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Failed to update pod: %v", err)
+	}
+	if err := pods.Delete(context.TODO(), "doomed", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Failed to delete pod: %v", err)
+	}
+	if _, err := pods.Create(context.TODO(), newPod("fresh", corev1.PodPending, 0), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Failed to create pod: %v", err)
+	}
+
+	result, err = watcher.Next(context.Background(), 15, 3, "")
+	if err != nil {
+		t.Fatalf("Failed to render second frame: %v", err)
+	}
+
+	for _, want := range []string{
+		"➖ default/doomed PodDeleted",
+		"🔄 default/flapping Restarted (+3 restarts)",
+		"➕ default/fresh PodCreated (Pending)",
+		"🔁 default/starting PhaseChanged (Pending → Running)",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Second frame does not contain %q:\n%s", want, result)
+		}
+	}
+	if strings.Contains(result, "default/stable") {
+		t.Errorf("Unchanged pod should not be reported:\n%s", result)
+	}
+}
+
+func TestInformerBackedClient(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:          "pod-1",
+			Namespace:     "default",
+			Annotations:   map[string]string{"kubectl.kubernetes.io/last-applied-configuration": "{}"},
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "app", Image: "app:1", Env: []corev1.EnvVar{{Name: "DEBUG", Value: "1"}}},
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "app", RestartCount: 2}},
+		},
+	}
+	if _, err := clientset.CoreV1().Pods(pod.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Failed to create fake pod: %v", err)
+	}
+
+	clientset.PrependReactor("list", "nodes", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(corev1.Resource("nodes"), "", fmt.Errorf("forbidden"))
+	})
+
+	service, err := NewServiceWithClientset(clientset)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	if err := service.StartInformers(context.Background(), ""); err != nil {
+		t.Fatalf("Failed to start informers: %v", err)
+	}
+	defer service.StopInformers()
+
+	cached := service.client.cache.informers[resourcePods].GetStore().List()
+	if len(cached) != 1 {
+		t.Fatalf("got %d cached pods, want 1", len(cached))
+	}
+	cachedPod := cached[0].(*corev1.Pod)
+	if cachedPod.ManagedFields != nil || cachedPod.Annotations != nil || cachedPod.Spec.Containers[0].Env != nil {
+		t.Errorf("expected cached pod to be trimmed, got %+v", cachedPod.ObjectMeta)
+	}
+
+	// Count List calls from here on; reads must come from the cache
+	var lists int
+	clientset.PrependReactor("list", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		lists++
+		return false, nil, nil
+	})
+
+	health, err := service.GetClusterHealth(context.Background(), 15, 3, "")
+	if err != nil {
+		t.Fatalf("Failed to get cluster health: %v", err)
+	}
+	if lists != 0 {
+		t.Errorf("expected no List calls while informers run, got %d", lists)
+	}
+	if health.PodStatusDistribution.Running != 1 || health.TopOffenders[0].Restarts != 2 {
+		t.Errorf("unexpected health from cache: %+v", health)
+	}
+	if health.Nodes != nil {
+		t.Errorf("expected forbidden nodes to be skipped, got %+v", health.Nodes)
+	}
+}
+
+func TestGetClusterHealthIncomplete(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	if _, err := clientset.CoreV1().Pods("default").Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Failed to create fake pod: %v", err)
+	}
+
+	// A slow workload list should not cost the rest of the pulse
+	clientset.PrependReactor("list", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewTimeoutError("request timed out", 1)
+	})
+
+	service, err := NewServiceWithClientset(clientset)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	health, err := service.GetClusterHealth(context.Background(), 15, 3, "")
+	if err != nil {
+		t.Fatalf("Expected timed out section to be tolerated, got: %v", err)
+	}
+	// Rollouts are read from deployments too
+	if want := []string{SectionWorkloads, SectionRollouts}; strings.Join(health.Incomplete, ",") != strings.Join(want, ",") {
+		t.Errorf("Incomplete = %v, want %v", health.Incomplete, want)
+	}
+	if health.Nodes == nil {
+		t.Error("Expected node health despite the workload timeout")
+	}
+	if health.Status != StatusWarning {
+		t.Errorf("status = %q, want %q for an incomplete pulse", health.Status, StatusWarning)
+	}
+
+	result, err := service.GetClusterPulse(context.Background(), 15, 3, "")
+	if err != nil {
+		t.Fatalf("Failed to get cluster pulse: %v", err)
+	}
+	if !strings.Contains(result, "⏳ Incomplete: workloads, rollouts timed out") {
+		t.Errorf("Expected incomplete sections in output:\n%s", result)
+	}
+	if !strings.Contains(result, "⏳ Workloads: not checked (timed out)") || strings.Contains(result, "all replicas available") {
+		t.Errorf("Expected unchecked workloads in output:\n%s", result)
+	}
+
+	// A check that did not fully run is UNKNOWN to monitoring
+	check, err := NewNagiosFormatter().FormatClusterHealth(health)
+	if err != nil {
+		t.Fatalf("Failed to format check result: %v", err)
+	}
+	if !strings.HasPrefix(check, "PULSE UNKNOWN - 2 sections timed out") || NagiosExitCode(health) != ExitCodeError {
+		t.Errorf("unexpected check result for an incomplete pulse (exit %d): %s", NagiosExitCode(health), check)
+	}
+
+	// Pods are required, so a cancelled pulse fails outright
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	clientset.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, ctx.Err()
+	})
+	if _, err := service.GetClusterHealth(ctx, 15, 3, ""); err == nil {
+		t.Error("Expected an error from a cancelled pulse")
+	}
+}
+
+func TestHealthPolicy(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+
+	pods := []corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "dev", CreationTimestamp: metav1.NewTime(time.Now().Add(-5 * time.Minute))},
+			Status: corev1.PodStatus{
+				Phase:             corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "app", RestartCount: 8}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "prod"},
+			Status:     corev1.PodStatus{Phase: corev1.PodPending},
+		},
+	}
+	for _, pod := range pods {
+		if _, err := clientset.CoreV1().Pods(pod.Namespace).Create(context.TODO(), &pod, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("Failed to create fake pod: %v", err)
+		}
+	}
+
+	service, err := NewServiceWithClientset(clientset)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	health, err := service.GetClusterHealth(context.Background(), 15, 3, "")
+	if err != nil {
+		t.Fatalf("Failed to get cluster health: %v", err)
+	}
+	if health.Status != StatusCritical {
+		t.Errorf("status = %q with the default policy, want %q", health.Status, StatusCritical)
+	}
+
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	policyFile := `
+pendingPods:
+  warning: 0
+offenderRestarts:
+  warning: 5
+certificateHorizon: 30d
+namespaces:
+  dev:
+    restarts:
+      warning: 10
+      critical: 50
+`
+	if err := os.WriteFile(path, []byte(policyFile), 0o644); err != nil {
+		t.Fatalf("Failed to write policy: %v", err)
+	}
+	policy, err := LoadHealthPolicy(path)
+	if err != nil {
+		t.Fatalf("Failed to load policy: %v", err)
+	}
+
+	if policy.CertificateHorizon.Duration != 30*24*time.Hour {
+		t.Errorf("certificate horizon = %s, want 30d", policy.CertificateHorizon)
+	}
+	if restarts := policy.For("prod").Restarts; *restarts.Warning != 0 || *restarts.Critical != 5 {
+		t.Errorf("prod restarts threshold = %d/%d, want the defaults 0/5", *restarts.Warning, *restarts.Critical)
+	}
+	if offenders := policy.For("dev").OffenderRestarts; *offenders.Warning != 5 || *offenders.Critical != 100 {
+		t.Errorf("dev offender threshold = %d/%d, want 5/100", *offenders.Warning, *offenders.Critical)
+	}
+
+	// dev tolerates its restarts, leaving the pending prod pod as the only finding
+	service.SetPolicy(policy)
+	health, err = service.GetClusterHealth(context.Background(), 15, 3, "")
+	if err != nil {
+		t.Fatalf("Failed to get cluster health: %v", err)
+	}
+	if health.Status != StatusWarning {
+		t.Errorf("status = %q with the policy, want %q", health.Status, StatusWarning)
+	}
+	if len(health.TopOffenders) == 0 || health.TopOffenders[0].Severity != StatusWarning {
+		t.Errorf("expected the dev pod to be a WARNING offender, got %+v", health.TopOffenders)
+	}
+
+	for name, content := range map[string]string{
+		"inverted": "restarts:\n  warning: 10\n  critical: 5\n",
+		"typo":     "restart:\n  warning: 10\n",
+	} {
+		invalid := filepath.Join(t.TempDir(), name+".yaml")
+		if err := os.WriteFile(invalid, []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write policy: %v", err)
+		}
+		if _, err := LoadHealthPolicy(invalid); err == nil {
+			t.Errorf("Expected %s policy to be rejected", name)
+		}
+	}
+}
+
+func TestHealthStatusExitCode(t *testing.T) {
+	for status, want := range map[HealthStatus]int{
+		StatusHealthy:  0,
+		StatusWarning:  1,
+		StatusCritical: 2,
+	} {
+		if got := status.ExitCode(); got != want {
+			t.Errorf("%s.ExitCode() = %d, want %d", status, got, want)
+		}
+	}
+}
+
+func TestNagiosFormatter(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", CreationTimestamp: metav1.NewTime(time.Now().Add(-5 * time.Minute))},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "app", RestartCount: 7}},
+		},
+	}
+	if _, err := clientset.CoreV1().Pods("default").Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Failed to create fake pod: %v", err)
+	}
+	for _, name := range []string{"node-a", "node-b"} {
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionFalse}},
+			},
+		}
+		if _, err := clientset.CoreV1().Nodes().Create(context.TODO(), node, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("Failed to create fake node: %v", err)
+		}
+	}
+
+	service, err := NewServiceWithClientset(clientset)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+	if err := service.SetOutput(OutputNagios); err != nil {
+		t.Fatalf("Failed to select nagios output: %v", err)
+	}
+
+	result, err := service.GetClusterPulse(context.Background(), 15, 3, "")
+	if err != nil {
+		t.Fatalf("Failed to get cluster pulse: %v", err)
+	}
+
+	for _, want := range []string{
+		"PULSE CRITICAL - 7 recent restarts, 2 nodes NotReady | ",
+		"restarts=7;0;5 ",
+		"pendingPods=0;; ",
+		"notReadyNodes=2;;0 ",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q in output:\n%s", want, result)
+		}
+	}
+	if strings.Contains(result, "\n") {
+		t.Errorf("Expected a single status line:\n%s", result)
+	}
+
+	fleet := NewFleetWithServices(map[string]*Service{"prod": service})
+	if err := fleet.SetOutput(OutputNagios); err != nil {
+		t.Fatalf("Failed to select nagios output: %v", err)
+	}
+	result, err = fleet.GetFleetPulse(context.Background(), 15, 3, "")
+	if err != nil {
+		t.Fatalf("Failed to get fleet pulse: %v", err)
+	}
+	if !strings.HasPrefix(result, "PULSE CRITICAL - prod CRITICAL | clusters=1;; ") ||
+		!strings.Contains(result, "\nprod: CRITICAL - 7 recent restarts, 2 nodes NotReady") {
+		t.Errorf("unexpected fleet output:\n%s", result)
+	}
+}
+
+func TestExporter(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", CreationTimestamp: metav1.NewTime(time.Now().Add(-5 * time.Minute))},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "app", RestartCount: 7}},
+		},
+	}
+	if _, err := clientset.CoreV1().Pods("default").Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Failed to create fake pod: %v", err)
+	}
+
+	service, err := NewServiceWithClientset(clientset)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	exporter := NewExporter(service, 15, 3, "")
+	scrape := func() string {
+		recorder := httptest.NewRecorder()
+		exporter.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("scrape returned %d", recorder.Code)
+		}
+		return recorder.Body.String()
+	}
+
+	if body := scrape(); !strings.Contains(body, "pulse_up 0") {
+		t.Errorf("Expected pulse_up 0 before the first refresh:\n%s", body)
+	}
+
+	if err := exporter.Refresh(context.Background()); err != nil {
+		t.Fatalf("Failed to refresh: %v", err)
+	}
+	body := scrape()
+	for _, want := range []string{
+		"pulse_up 1",
+		"pulse_health_level 2",
+		`pulse_restarts{namespace="default"} 7`,
+		`pulse_pods{phase="Running"} 1`,
+		`pulse_top_offender_restarts{namespace="default",pod="app"} 7`,
+		`pulse_signal{signal="restarts"} 7`,
+		`pulse_signal_level{signal="restarts"} 2`,
+		"pulse_scrape_duration_seconds ",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected %q in metrics:\n%s", want, body)
+		}
+	}
+
+	// A failed refresh keeps the previous pulse but reports it as down
+	clientset.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, fmt.Errorf("connection refused")
+	})
+	if err := exporter.Refresh(context.Background()); err == nil {
+		t.Fatal("Expected refresh to fail")
+	}
+	body = scrape()
+	if !strings.Contains(body, "pulse_up 0") || !strings.Contains(body, `pulse_restarts{namespace="default"} 7`) {
+		t.Errorf("Expected the previous pulse with pulse_up 0:\n%s", body)
+	}
+}
+
+func TestGetClusterPulseWarningEvents(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	now := time.Now()
+
+	events := []eventsv1.Event{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "api.1", Namespace: "default"},
+			Type:       "Warning",
+			Reason:     "FailedScheduling",
+			Note:       "0/3 nodes are available: 3 Insufficient cpu.",
+			Regarding:  corev1.ObjectReference{Kind: "Pod", Namespace: "default", Name: "api"},
+			EventTime:  metav1.NewMicroTime(now.Add(-10 * time.Minute)),
+			Series:     &eventsv1.EventSeries{Count: 6, LastObservedTime: metav1.NewMicroTime(now.Add(-time.Minute))},
+		},
+		{
+			ObjectMeta:      metav1.ObjectMeta{Name: "worker.1", Namespace: "default"},
+			Type:            "Warning",
+			Reason:          "FailedScheduling",
+			Note:            "0/3 nodes are available: 3 Insufficient memory.",
+			Regarding:       corev1.ObjectReference{Kind: "Pod", Namespace: "default", Name: "worker"},
+			EventTime:       metav1.NewMicroTime(now.Add(-2 * time.Minute)),
+			DeprecatedCount: 2,
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "api.2", Namespace: "default"},
+			Type:       "Normal",
+			Reason:     "Scheduled",
+			Regarding:  corev1.ObjectReference{Kind: "Pod", Namespace: "default", Name: "api"},
+			EventTime:  metav1.NewMicroTime(now.Add(-time.Minute)),
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "old.1", Namespace: "default"},
+			Type:       "Warning",
+			Reason:     "BackOff",
+			Regarding:  corev1.ObjectReference{Kind: "Pod", Namespace: "default", Name: "old"},
+			EventTime:  metav1.NewMicroTime(now.Add(-2 * time.Hour)),
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1.1", Namespace: "default"},
+			Type:       "Normal",
+			Reason:     "NodeNotReady",
+			Note:       "Node node-1 status is now: NodeNotReady",
+			Regarding:  corev1.ObjectReference{Kind: "Node", Name: "node-1"},
+			EventTime:  metav1.NewMicroTime(now.Add(-3 * time.Minute)),
+		},
+	}
+	for _, event := range events {
+		if _, err := clientset.EventsV1().Events(event.Namespace).Create(context.TODO(), &event, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("Failed to create fake event: %v", err)
+		}
+	}
+
+	service, err := NewServiceWithClientset(clientset)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	health, err := service.GetClusterHealth(context.Background(), 15, 3, "")
+	if err != nil {
+		t.Fatalf("Failed to get cluster health: %v", err)
+	}
+
+	if len(health.WarningEvents) != 2 {
+		t.Fatalf("expected FailedScheduling and NodeNotReady, got %+v", health.WarningEvents)
+	}
+	scheduling := health.WarningEvents[0]
+	if scheduling.Reason != "FailedScheduling" || scheduling.Count != 8 {
+		t.Errorf("expected 8 FailedScheduling occurrences from the series and legacy count, got %+v", scheduling)
+	}
+	if len(scheduling.Objects) != 2 || scheduling.Objects[0].Name != "api" || scheduling.Objects[0].Count != 6 {
+		t.Errorf("expected api to be the most affected object, got %+v", scheduling.Objects)
+	}
+	if nodeNotReady := health.WarningEvents[1]; nodeNotReady.Reason != "NodeNotReady" || nodeNotReady.Objects[0].Kind != "Node" {
+		t.Errorf("expected NodeNotReady for node-1, got %+v", nodeNotReady)
+	}
+
+	result, err := service.GetClusterPulse(context.Background(), 15, 3, "")
+	if err != nil {
+		t.Fatalf("Failed to get cluster pulse: %v", err)
+	}
+	if !strings.Contains(result, "FailedScheduling ×8 (2 objects)") || !strings.Contains(result, "└ Pod default/api ×6: 0/3 nodes are available") {
+		t.Errorf("Expected warning events in output:\n%s", result)
+	}
+
+	// Clusters without events.k8s.io access fall back to core/v1
+	clientset.PrependReactor("list", "events", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetResource().Group == "events.k8s.io" {
+			return true, nil, apierrors.NewForbidden(eventsv1.Resource("events"), "", fmt.Errorf("forbidden"))
+		}
+		return false, nil, nil
+	})
+	coreEvent := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "db.1", Namespace: "default"},
+		Type:           "Warning",
+		Reason:         "FailedMount",
+		Message:        "MountVolume.SetUp failed",
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "default", Name: "db"},
+		Count:          3,
+		LastTimestamp:  metav1.NewTime(now.Add(-time.Minute)),
+	}
+	if _, err := clientset.CoreV1().Events("default").Create(context.TODO(), coreEvent, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Failed to create fake event: %v", err)
+	}
+
+	health, err = service.GetClusterHealth(context.Background(), 15, 3, "")
+	if err != nil {
+		t.Fatalf("Failed to get cluster health: %v", err)
+	}
+	if len(health.WarningEvents) != 1 || health.WarningEvents[0].Reason != "FailedMount" || health.WarningEvents[0].Count != 3 {
+		t.Errorf("expected the core/v1 FailedMount event, got %+v", health.WarningEvents)
+	}
+}
+
+func TestGetClusterPulsePendingPods(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	now := time.Now()
+
+	pending := func(name string, age time.Duration, status corev1.PodStatus) corev1.Pod {
+		status.Phase = corev1.PodPending
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", CreationTimestamp: metav1.NewTime(now.Add(-age))},
+			Status:     status,
+		}
+	}
+	scheduled := []corev1.PodCondition{{Type: corev1.PodScheduled, Status: corev1.ConditionTrue}}
+
+	pods := []corev1.Pod{
+		pending("unschedulable", 20*time.Minute, corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{
+				Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: corev1.PodReasonUnschedulable,
+				Message: "0/3 nodes are available: 3 Insufficient cpu.",
+			}},
+		}),
+		pending("claim", 10*time.Minute, corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{
+				Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: corev1.PodReasonUnschedulable,
+				Message: "0/3 nodes are available: pod has unbound immediate PersistentVolumeClaims.",
+			}},
+		}),
+		pending("image", 5*time.Minute, corev1.PodStatus{
+			Conditions: scheduled,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "app",
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: `Back-off pulling image "nope"`}},
+			}},
+		}),
+		pending("init", 3*time.Minute, corev1.PodStatus{
+			Conditions: scheduled,
+			InitContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "wait-for-db",
+				State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: metav1.NewTime(now.Add(-3 * time.Minute))}},
+			}},
+		}),
+		pending("fresh", time.Minute, corev1.PodStatus{}),
+	}
+	for _, pod := range pods {
+		if _, err := clientset.CoreV1().Pods(pod.Namespace).Create(context.TODO(), &pod, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("Failed to create fake pod: %v", err)
+		}
+	}
+
+	service, err := NewServiceWithClientset(clientset)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	health, err := service.GetClusterHealth(context.Background(), 15, 3, "")
+	if err != nil {
+		t.Fatalf("Failed to get cluster health: %v", err)
+	}
+
+	want := []struct{ pod, cause string }{
+		{"unschedulable", PendingUnschedulable},
+		{"claim", PendingVolumeBinding},
+		{"image", PendingImagePull},
+		{"init", PendingInitContainers},
+		{"fresh", PendingAwaitingScheduler},
+	}
+	if len(health.PendingPods) != len(want) {
+		t.Fatalf("expected %d pending pods, got %+v", len(want), health.PendingPods)
+	}
+	for i, w := range want {
+		if got := health.PendingPods[i]; got.Pod != w.pod || got.Cause != w.cause {
+			t.Errorf("pending pod %d = %s %s, want %s %s", i, got.Pod, got.Cause, w.pod, w.cause)
+		}
+	}
+
+	result, err := service.GetClusterPulse(context.Background(), 15, 3, "")
+	if err != nil {
+		t.Fatalf("Failed to get cluster pulse: %v", err)
+	}
+	for _, line := range []string{
+		"🕐 default/unschedulable pending 20m: Unschedulable - 0/3 nodes are available: 3 Insufficient cpu.",
+		`🕐 default/image pending 5m: ImagePull - app: ImagePullBackOff - Back-off pulling image "nope"`,
+		"🕐 default/init pending 3m: InitContainers - wait-for-db: running since",
+	} {
+		if !strings.Contains(result, line) {
+			t.Errorf("Expected %q in output:\n%s", line, result)
+		}
+	}
+}
+
+func TestGetClusterPulseStorageIssues(t *testing.T) {
+	now := time.Now()
+	waitForConsumer := storagev1.VolumeBindingWaitForFirstConsumer
+	standard, local := "standard", "local"
+
+	claim := func(name string, class *string, age time.Duration, phase corev1.PersistentVolumeClaimPhase) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", CreationTimestamp: metav1.NewTime(now.Add(-age))},
+			Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: class},
+			Status:     corev1.PersistentVolumeClaimStatus{Phase: phase},
+		}
+	}
+	volume := func(name, namespace, claim string, phase corev1.PersistentVolumePhase) *corev1.PersistentVolume {
+		return &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: corev1.PersistentVolumeSpec{
+				ClaimRef:                      &corev1.ObjectReference{Namespace: namespace, Name: claim},
+				PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimRetain,
+			},
+			Status: corev1.PersistentVolumeStatus{Phase: phase},
+		}
+	}
+	volumeName := "pv-db"
+
+	clientset := fake.NewSimpleClientset(
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: local}, VolumeBindingMode: &waitForConsumer},
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: standard}},
+		claim("data-db-0", &standard, 20*time.Minute, corev1.ClaimPending),
+		claim("data-cache-0", &local, 20*time.Minute, corev1.ClaimPending),
+		claim("fresh", &standard, time.Minute, corev1.ClaimPending),
+		claim("gone", &standard, time.Hour, corev1.ClaimLost),
+		claim("bound", &standard, time.Hour, corev1.ClaimBound),
+		volume("pv-db", "default", "bound", corev1.VolumeBound),
+		volume("pv-old", "default", "deleted", corev1.VolumeReleased),
+		volume("pv-other", "other", "deleted", corev1.VolumeReleased),
+		&storagev1.VolumeAttachment{
+			ObjectMeta: metav1.ObjectMeta{Name: "csi-123"},
+			Spec: storagev1.VolumeAttachmentSpec{
+				NodeName: "node-1",
+				Source:   storagev1.VolumeAttachmentSource{PersistentVolumeName: &volumeName},
+			},
+			Status: storagev1.VolumeAttachmentStatus{AttachError: &storagev1.VolumeError{Message: "volume is attached to another node"}},
+		},
+		&eventsv1.Event{
+			ObjectMeta: metav1.ObjectMeta{Name: "db-0.1", Namespace: "default"},
+			Type:       "Warning",
+			Reason:     "FailedMount",
+			Note:       "MountVolume.SetUp failed for volume \"data\"",
+			Regarding:  corev1.ObjectReference{Kind: "Pod", Namespace: "default", Name: "db-0"},
+			EventTime:  metav1.NewMicroTime(now.Add(-time.Minute)),
+		},
+		&eventsv1.Event{
+			ObjectMeta: metav1.ObjectMeta{Name: "data-db-0.1", Namespace: "default"},
+			Type:       "Warning",
+			Reason:     "ProvisioningFailed",
+			Note:       "storageclass.storage.k8s.io \"standard\" not found",
+			Regarding:  corev1.ObjectReference{Kind: "PersistentVolumeClaim", Namespace: "default", Name: "data-db-0"},
+			EventTime:  metav1.NewMicroTime(now.Add(-2 * time.Minute)),
+		},
+	)
+
+	service, err := NewServiceWithClientset(clientset)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	health, err := service.GetClusterHealth(context.Background(), 15, 3, "default")
+	if err != nil {
+		t.Fatalf("Failed to get cluster health: %v", err)
+	}
+
+	want := []StorageIssue{
+		{Kind: "PersistentVolume", Name: "pv-old", Status: "Released", Message: "claim default/deleted was deleted, reclaim policy Retain"},
+		{Kind: "VolumeAttachment", Name: "csi-123", Status: StorageAttachError, Message: "pv-db on node-1: volume is attached to another node"},
+		{Kind: "PersistentVolumeClaim", Namespace: "default", Name: "data-db-0", Status: "Pending", Message: `storageclass.storage.k8s.io "standard" not found`},
+		{Kind: "PersistentVolumeClaim", Namespace: "default", Name: "gone", Status: "Lost", Message: "bound volume no longer exists"},
+		{Kind: "Pod", Namespace: "default", Name: "db-0", Status: "FailedMount", Message: `MountVolume.SetUp failed for volume "data"`},
+	}
+	if len(health.StorageIssues) != len(want) {
+		t.Fatalf("expected %d storage issues, got %+v", len(want), health.StorageIssues)
+	}
+	for i, w := range want {
+		got := health.StorageIssues[i]
+		got.Since = time.Time{}
+		if got != w {
+			t.Errorf("storage issue %d = %+v, want %+v", i, got, w)
+		}
+	}
+	if health.Status != StatusWarning {
+		t.Errorf("expected WARNING status for storage issues, got %s", health.Status)
+	}
+
+	result, err := service.GetClusterPulse(context.Background(), 15, 3, "default")
+	if err != nil {
+		t.Fatalf("Failed to get cluster pulse: %v", err)
+	}
+	for _, line := range []string{
+		"💾 Storage issues: 5",
+		`🟡 PersistentVolumeClaim default/data-db-0 Pending for 20m: storageclass.storage.k8s.io "standard" not found`,
+		"🔴 VolumeAttachment csi-123 AttachError: pv-db on node-1: volume is attached to another node",
+	} {
+		if !strings.Contains(result, line) {
+			t.Errorf("Expected %q in output:\n%s", line, result)
+		}
+	}
+}
+
+func TestGetClusterPulseUnhealthyServices(t *testing.T) {
+	ready, notReady, terminating := true, false, true
+
+	svc := func(name string, serviceType corev1.ServiceType, selector map[string]string) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       corev1.ServiceSpec{Type: serviceType, Selector: selector},
+		}
+	}
+	slice := func(name, service string, addressType discoveryv1.AddressType, conditions ...discoveryv1.EndpointConditions) *discoveryv1.EndpointSlice {
+		endpoints := make([]discoveryv1.Endpoint, len(conditions))
+		for i, condition := range conditions {
+			endpoints[i] = discoveryv1.Endpoint{Addresses: []string{fmt.Sprintf("10.0.0.%d", i)}, Conditions: condition}
+		}
+		return &discoveryv1.EndpointSlice{
+			ObjectMeta:  metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{discoveryv1.LabelServiceName: service}},
+			AddressType: addressType,
+			Endpoints:   endpoints,
+		}
+	}
+	pod := func(name string, labels map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+
+	clientset := fake.NewSimpleClientset(
+		svc("web", corev1.ServiceTypeClusterIP, map[string]string{"app": "web"}),
+		svc("api", corev1.ServiceTypeClusterIP, map[string]string{"app": "api"}),
+		svc("typo", corev1.ServiceTypeClusterIP, map[string]string{"app": "wbe"}),
+		svc("db", corev1.ServiceTypeClusterIP, map[string]string{"app": "db"}),
+		svc("external", corev1.ServiceTypeExternalName, nil),
+		svc("manual", corev1.ServiceTypeClusterIP, nil),
+		// web is dual-stack with one of three endpoints ready
+		slice("web-v4", "web", discoveryv1.AddressTypeIPv4,
+			discoveryv1.EndpointConditions{Ready: &ready},
+			discoveryv1.EndpointConditions{Ready: &notReady},
+			discoveryv1.EndpointConditions{Ready: &notReady}),
+		slice("web-v6", "web", discoveryv1.AddressTypeIPv6,
+			discoveryv1.EndpointConditions{Ready: &ready},
+			discoveryv1.EndpointConditions{Ready: &notReady},
+			discoveryv1.EndpointConditions{Ready: &notReady}),
+		// api is healthy once its terminating endpoint is left out
+		slice("api", "api", discoveryv1.AddressTypeIPv4,
+			discoveryv1.EndpointConditions{},
+			discoveryv1.EndpointConditions{Ready: &notReady, Terminating: &terminating},
+			discoveryv1.EndpointConditions{Ready: &notReady, Terminating: &terminating}),
+		slice("db", "db", discoveryv1.AddressTypeIPv4,
+			discoveryv1.EndpointConditions{Ready: &notReady}),
+		pod("web-1", map[string]string{"app": "web", "pod-template-hash": "abc"}),
+		pod("web-2", map[string]string{"app": "web", "pod-template-hash": "abc"}),
+		pod("web-3", map[string]string{"app": "web", "pod-template-hash": "abc"}),
+		pod("db-0", map[string]string{"app": "db"}),
+	)
+
+	service, err := NewServiceWithClientset(clientset)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	health, err := service.GetClusterHealth(context.Background(), 15, 3, "")
+	if err != nil {
+		t.Fatalf("Failed to get cluster health: %v", err)
+	}
+
+	want := []struct {
+		name                      string
+		ready, endpoints, matched int
+	}{
+		{"db", 0, 1, 1},
+		{"typo", 0, 0, 0},
+		{"web", 1, 3, 3},
+	}
+	if len(health.UnhealthyServices) != len(want) {
+		t.Fatalf("expected %d unhealthy services, got %+v", len(want), health.UnhealthyServices)
+	}
+	for i, w := range want {
+		got := health.UnhealthyServices[i]
+		if got.Name != w.name || got.ReadyEndpoints != w.ready || got.Endpoints != w.endpoints || got.Pods != w.matched {
+			t.Errorf("unhealthy service %d = %+v, want %+v", i, got, w)
+		}
+	}
+	if health.Status != StatusCritical {
+		t.Errorf("expected CRITICAL status for services without ready endpoints, got %s", health.Status)
+	}
+
+	result, err := service.GetClusterPulse(context.Background(), 15, 3, "")
+	if err != nil {
+		t.Fatalf("Failed to get cluster pulse: %v", err)
+	}
+	for _, line := range []string{
+		"🔌 Unhealthy services: 3",
+		"🔴 default/typo: 0/0 endpoints ready, 0 pods match app=wbe",
+		"🟡 default/web: 1/3 endpoints ready, 3 pods match app=web",
+	} {
+		if !strings.Contains(result, line) {
+			t.Errorf("Expected %q in output:\n%s", line, result)
+		}
+	}
+}
+
+func TestGetClusterPulseRouteIssues(t *testing.T) {
+	ready := true
+	pathType := networkingv1.PathTypePrefix
+
+	clientset := fake.NewSimpleClientset(
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec: corev1.ServiceSpec{
+				Selector: map[string]string{"app": "web"},
+				Ports:    []corev1.ServicePort{{Name: "http", Port: 80}},
+			},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+			Spec: corev1.ServiceSpec{
+				Selector: map[string]string{"app": "api"},
+				Ports:    []corev1.ServicePort{{Name: "http", Port: 8080}},
+			},
+		},
+		&discoveryv1.EndpointSlice{
+			ObjectMeta:  metav1.ObjectMeta{Name: "web", Namespace: "default", Labels: map[string]string{discoveryv1.LabelServiceName: "web"}},
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Endpoints:   []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.1"}, Conditions: discoveryv1.EndpointConditions{Ready: &ready}}},
+		},
+		&networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "default"},
+			Spec: networkingv1.IngressSpec{
+				Rules: []networkingv1.IngressRule{{
+					Host: "shop.example.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{
+							{Path: "/", PathType: &pathType, Backend: networkingv1.IngressBackend{
+								Service: &networkingv1.IngressServiceBackend{Name: "web", Port: networkingv1.ServiceBackendPort{Name: "http"}},
+							}},
+							{Path: "/static", PathType: &pathType, Backend: networkingv1.IngressBackend{
+								Service: &networkingv1.IngressServiceBackend{Name: "web", Port: networkingv1.ServiceBackendPort{Number: 8080}},
+							}},
+							{Path: "/cart", PathType: &pathType, Backend: networkingv1.IngressBackend{
+								Service: &networkingv1.IngressServiceBackend{Name: "cart", Port: networkingv1.ServiceBackendPort{Number: 80}},
+							}},
+						},
+					}},
+				}},
+			},
+		},
+	)
+
+	route := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "gateway.networking.k8s.io/v1",
+		"kind":       "HTTPRoute",
+		"metadata":   map[string]any{"name": "api", "namespace": "default"},
+		"spec": map[string]any{
+			"rules": []any{
+				map[string]any{"backendRefs": []any{
+					map[string]any{"name": "api", "port": int64(8080)},
+					map[string]any{"name": "web", "namespace": "other", "port": int64(80)},
+				}},
+			},
+		},
+		"status": map[string]any{
+			"parents": []any{
+				map[string]any{
+					"parentRef": map[string]any{"name": "public"},
+					"conditions": []any{
+						map[string]any{"type": "Accepted", "status": "True", "reason": "Accepted"},
+						map[string]any{"type": "ResolvedRefs", "status": "False", "reason": "RefNotPermitted", "message": "missing ReferenceGrant"},
+					},
+				},
+			},
+		},
+	}}
+	httpRoutes := schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1", Resource: "httproutes"}
+	certificates := schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{httpRoutes: "HTTPRouteList", certificates: "CertificateList"}, route)
+
+	service, err := NewServiceWithClients(clientset, dynamicClient)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	health, err := service.GetClusterHealth(context.Background(), 15, 3, "default")
+	if err != nil {
+		t.Fatalf("Failed to get cluster health: %v", err)
+	}
+
+	want := []RouteIssue{
+		{Kind: "HTTPRoute", Namespace: "default", Name: "api", Reason: RouteNoReadyEndpoints, Backend: "api:8080", Message: "service api has 0 endpoints, none ready"},
+		{Kind: "HTTPRoute", Namespace: "default", Name: "api", Reason: RouteRefsNotResolved, Message: "public: RefNotPermitted - missing ReferenceGrant"},
+		{Kind: "Ingress", Namespace: "default", Name: "shop", Reason: RoutePortNotFound, Backend: "web:8080", Message: "service web does not expose port 8080"},
+		{Kind: "Ingress", Namespace: "default", Name: "shop", Reason: RouteServiceNotFound, Backend: "cart:80", Message: "service cart not found"},
+	}
+	if len(health.RouteIssues) != len(want) {
+		t.Fatalf("expected %d route issues, got %+v", len(want), health.RouteIssues)
+	}
+	for i, w := range want {
+		if got := health.RouteIssues[i]; got != w {
+			t.Errorf("route issue %d = %+v, want %+v", i, got, w)
+		}
+	}
+
+	result, err := service.GetClusterPulse(context.Background(), 15, 3, "default")
+	if err != nil {
+		t.Fatalf("Failed to get cluster pulse: %v", err)
+	}
+	if line := "🟡 Ingress default/shop → cart:80: ServiceNotFound - service cart not found"; !strings.Contains(result, line) {
+		t.Errorf("Expected %q in output:\n%s", line, result)
+	}
+
+	// Without the Gateway API only Ingresses are checked
+	service, err = NewServiceWithClientset(clientset)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+	health, err = service.GetClusterHealth(context.Background(), 15, 3, "default")
+	if err != nil {
+		t.Fatalf("Failed to get cluster health: %v", err)
+	}
+	if len(health.RouteIssues) != 2 {
+		t.Errorf("expected only the 2 Ingress issues without the Gateway API, got %+v", health.RouteIssues)
+	}
+
+	// Nor when the Gateway API CRDs are not installed
+	dynamicClient = dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{httpRoutes: "HTTPRouteList", certificates: "CertificateList"})
+	dynamicClient.PrependReactor("list", "httproutes", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewNotFound(httpRoutes.GroupResource(), "")
+	})
+	service, err = NewServiceWithClients(clientset, dynamicClient)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+	health, err = service.GetClusterHealth(context.Background(), 15, 3, "default")
+	if err != nil {
+		t.Fatalf("Failed to get cluster health without the Gateway API CRDs: %v", err)
+	}
+	if len(health.RouteIssues) != 2 {
+		t.Errorf("expected only the 2 Ingress issues without the Gateway API CRDs, got %+v", health.RouteIssues)
+	}
+}
+
+// selfSignedCertificate returns a PEM certificate for host valid until
+// notAfter.
+func selfSignedCertificate(t *testing.T, host string, notAfter time.Time) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: host},
+		DNSNames:     []string{host},
+		NotBefore:    notAfter.Add(-90 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestGetClusterPulseCertificates(t *testing.T) {
+	now := time.Now()
+	tlsSecret := func(name string, cert []byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Type:       corev1.SecretTypeTLS,
+			Data:       map[string][]byte{corev1.TLSCertKey: cert, corev1.TLSPrivateKeyKey: []byte("key")},
+		}
+	}
+
+	clientset := fake.NewSimpleClientset(
+		tlsSecret("expired-tls", selfSignedCertificate(t, "old.example.com", now.Add(-48*time.Hour))),
+		tlsSecret("soon-tls", selfSignedCertificate(t, "soon.example.com", now.Add(5*24*time.Hour))),
+		tlsSecret("fine-tls", selfSignedCertificate(t, "fine.example.com", now.Add(60*24*time.Hour))),
+		tlsSecret("broken-tls", []byte("not a certificate")),
+		// Managed by the shop Certificate, which reports on it instead
+		tlsSecret("shop-tls", selfSignedCertificate(t, "shop.example.com", now.Add(24*time.Hour))),
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "password", Namespace: "default"},
+			Type:       corev1.SecretTypeOpaque,
+		},
+	)
+
+	certificate := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "cert-manager.io/v1",
+		"kind":       "Certificate",
+		"metadata":   map[string]any{"name": "shop", "namespace": "default"},
+		"spec":       map[string]any{"secretName": "shop-tls", "dnsNames": []any{"shop.example.com"}},
+		"status": map[string]any{
+			"notAfter": now.Add(24 * time.Hour).UTC().Format(time.RFC3339),
+			"conditions": []any{
+				map[string]any{"type": "Ready", "status": "False", "reason": "Failed", "message": "ACME challenge failed"},
+			},
+		},
+	}}
+	certificates := schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+	httpRoutes := schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1", Resource: "httproutes"}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{httpRoutes: "HTTPRouteList", certificates: "CertificateList"}, certificate)
+
+	service, err := NewServiceWithClients(clientset, dynamicClient)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	health, err := service.GetClusterHealth(context.Background(), 15, 3, "")
+	if err != nil {
+		t.Fatalf("Failed to get cluster health: %v", err)
+	}
+
+	if health.Certificates == nil {
+		t.Fatal("expected a certificates section")
+	}
+	if health.Certificates.Checked != 5 {
+		t.Errorf("expected 5 certificates checked, got %d", health.Certificates.Checked)
+	}
+	want := []struct{ name, status string }{
+		{"expired-tls", CertificateExpired},
+		{"shop", CertificateNotReady},
+		{"soon-tls", CertificateExpiring},
+		{"broken-tls", CertificateInvalid},
+	}
+	if len(health.Certificates.Issues) != len(want) {
+		t.Fatalf("expected %d certificate issues, got %+v", len(want), health.Certificates.Issues)
+	}
+	for i, w := range want {
+		if got := health.Certificates.Issues[i]; got.Name != w.name || got.Status != w.status {
+			t.Errorf("certificate issue %d = %s %s, want %s %s", i, got.Name, got.Status, w.name, w.status)
+		}
+	}
+	if health.Status != StatusCritical {
+		t.Errorf("expected CRITICAL status for an expired certificate, got %s", health.Status)
+	}
+
+	result, err := service.GetClusterPulse(context.Background(), 15, 3, "")
+	if err != nil {
+		t.Fatalf("Failed to get cluster pulse: %v", err)
+	}
+	for _, line := range []string{
+		"🔐 Certificate issues: 4 (horizon 14d)",
+		"🔴 Secret default/expired-tls (old.example.com): Expired, expired 2d0h ago",
+		"🟡 Certificate default/shop (shop.example.com): NotReady, expires in 23h59m - Failed - ACME challenge failed",
+	} {
+		if !strings.Contains(result, line) {
+			t.Errorf("Expected %q in output:\n%s", line, result)
+		}
+	}
+
+	// A shorter horizon leaves the certificate expiring in 5 days out
+	policy := DefaultHealthPolicy()
+	policy.CertificateHorizon = Duration{3 * 24 * time.Hour}
+	service.SetPolicy(policy)
+	health, err = service.GetClusterHealth(context.Background(), 15, 3, "")
+	if err != nil {
+		t.Fatalf("Failed to get cluster health: %v", err)
+	}
+	if len(health.Certificates.Issues) != 3 {
+		t.Errorf("expected 3 certificate issues with a 3d horizon, got %+v", health.Certificates.Issues)
+	}
+
+	// Without access to Secrets the Certificates are still checked
+	clientset.PrependReactor("list", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(corev1.Resource("secrets"), "", fmt.Errorf("forbidden"))
+	})
+	health, err = service.GetClusterHealth(context.Background(), 15, 3, "")
+	if err != nil {
+		t.Fatalf("Failed to get cluster health without access to Secrets: %v", err)
+	}
+	if certificates := health.Certificates; certificates == nil || !certificates.SecretsForbidden || len(certificates.Issues) != 1 {
+		t.Errorf("expected only the Certificate to be checked without access to Secrets, got %+v", certificates)
+	}
+}
+
+func TestGetClusterPulseAutoscalers(t *testing.T) {
+	int32Ptr := func(n int32) *int32 { return &n }
+
+	hpa := func(name string, current, max int32, conditions ...autoscalingv2.HorizontalPodAutoscalerCondition) *autoscalingv2.HorizontalPodAutoscaler {
+		return &autoscalingv2.HorizontalPodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+				ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{Kind: "Deployment", Name: name},
+				MinReplicas:    int32Ptr(2),
+				MaxReplicas:    max,
+				Metrics: []autoscalingv2.MetricSpec{{
+					Type: autoscalingv2.ResourceMetricSourceType,
+					Resource: &autoscalingv2.ResourceMetricSource{
+						Name:   corev1.ResourceCPU,
+						Target: autoscalingv2.MetricTarget{Type: autoscalingv2.UtilizationMetricType, AverageUtilization: int32Ptr(70)},
+					},
+				}},
+			},
+			Status: autoscalingv2.HorizontalPodAutoscalerStatus{
+				CurrentReplicas: current,
+				DesiredReplicas: current,
+				CurrentMetrics: []autoscalingv2.MetricStatus{{
+					Type: autoscalingv2.ResourceMetricSourceType,
+					Resource: &autoscalingv2.ResourceMetricStatus{
+						Name:    corev1.ResourceCPU,
+						Current: autoscalingv2.MetricValueStatus{AverageUtilization: int32Ptr(92)},
+					},
+				}},
+				Conditions: conditions,
+			},
+		}
+	}
+
+	clientset := fake.NewSimpleClientset(
+		hpa("web", 10, 10, autoscalingv2.HorizontalPodAutoscalerCondition{
+			Type: autoscalingv2.ScalingLimited, Status: corev1.ConditionTrue, Reason: "TooManyReplicas",
+			Message: "the desired replica count is more than the maximum replica count",
+		}),
+		hpa("pinned", 2, 2),
+		hpa("quiet", 2, 10, autoscalingv2.HorizontalPodAutoscalerCondition{
+			Type: autoscalingv2.ScalingLimited, Status: corev1.ConditionTrue, Reason: "TooFewReplicas",
+			Message: "the desired replica count is less than the minimum replica count",
+		}),
+		hpa("api", 3, 10, autoscalingv2.HorizontalPodAutoscalerCondition{
+			Type: autoscalingv2.ScalingActive, Status: corev1.ConditionFalse, Reason: "FailedGetResourceMetric",
+			Message: "unable to get metrics for resource cpu",
+		}),
+		hpa("idle", 0, 10, autoscalingv2.HorizontalPodAutoscalerCondition{
+			Type: autoscalingv2.ScalingActive, Status: corev1.ConditionFalse, Reason: "ScalingDisabled",
+		}),
+		hpa("fine", 4, 10, autoscalingv2.HorizontalPodAutoscalerCondition{
+			Type: autoscalingv2.ScalingActive, Status: corev1.ConditionTrue, Reason: "ValidMetricFound",
+		}),
+	)
+
+	service, err := NewServiceWithClientset(clientset)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	health, err := service.GetClusterHealth(context.Background(), 15, 3, "")
+	if err != nil {
+		t.Fatalf("Failed to get cluster health: %v", err)
+	}
+
+	want := []struct {
+		name     string
+		problems string
+	}{
+		{"api", AutoscalerScalingInactive},
+		{"web", AutoscalerAtMaxReplicas + "," + AutoscalerScalingLimited},
+	}
+	if len(health.Autoscalers) != len(want) {
+		t.Fatalf("expected %d autoscaler issues, got %+v", len(want), health.Autoscalers)
+	}
+	for i, w := range want {
+		if got := health.Autoscalers[i]; got.Name != w.name || strings.Join(got.Problems, ",") != w.problems {
+			t.Errorf("autoscaler %d = %s %v, want %s %s", i, got.Name, got.Problems, w.name, w.problems)
+		}
+	}
+	if metrics := health.Autoscalers[1].Metrics; len(metrics) != 1 || metrics[0] != (AutoscalerMetric{Name: "cpu", Current: "92%", Target: "70%"}) {
+		t.Errorf("unexpected metrics %+v", metrics)
+	}
+	if health.Status != StatusWarning {
+		t.Errorf("expected WARNING status for autoscaler issues, got %s", health.Status)
+	}
+
+	result, err := service.GetClusterPulse(context.Background(), 15, 3, "")
+	if err != nil {
+		t.Fatalf("Failed to get cluster pulse: %v", err)
+	}
+	for _, line := range []string{
+		"📈 Autoscaler issues: 2",
+		"🟡 default/web → Deployment/web 10/10 replicas (min 2): AtMaxReplicas, ScalingLimited [cpu 92%/70%] - TooManyReplicas - the desired replica count is more than the maximum replica count",
+		"🟡 default/api → Deployment/api 3/10 replicas (min 2): ScalingInactive [cpu 92%/70%] - FailedGetResourceMetric - unable to get metrics for resource cpu",
+	} {
+		if !strings.Contains(result, line) {
+			t.Errorf("Expected %q in output:\n%s", line, result)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package pulse
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// restartHistoryRetention bounds how far back samples are kept; it only
+	// needs to cover the largest time window anyone asks for.
+	restartHistoryRetention = 24 * time.Hour
+	// restartHistoryMaxSamples caps the samples kept per pod.
+	restartHistoryMaxSamples = 64
+	// restartHistorySpacing is the minimum gap between samples. Runs closer
+	// together than this, as in watch mode, update the latest sample instead
+	// of crowding older baselines out of the history.
+	restartHistorySpacing = time.Minute
+)
+
+// restartBaselineSlack is how far before the window start a sample may be
+// taken and still stand in for the count at the window start. Restarts in
+// the slack are counted as recent, so it is kept to half the window.
+func restartBaselineSlack(window time.Duration) time.Duration {
+	return max(restartHistorySpacing, window/2)
+}
+
+// RestartSample is a pod's total restart count observed at a point in time.
+type RestartSample struct {
+	Time     time.Time `json:"time"`
+	Restarts int32     `json:"restarts"`
+}
+
+// GenerationLag is when a Deployment was first seen with its current
+// generation not yet observed by the controller.
+type GenerationLag struct {
+	Generation int64     `json:"generation"`
+	Since      time.Time `json:"since"`
+}
+
+// RestartHistory keeps restart counts sampled on previous invocations so the
+// number of restarts within a window can be measured instead of guessed from
+// each container's last termination. Samples are keyed by pod UID, so a
+// recreated pod starts a fresh history. It also keeps the generation lags of
+// Deployments, keyed by namespace/name, so they can be told to persist.
+type RestartHistory struct {
+	path           string
+	saved          time.Time
+	Samples        map[string][]RestartSample `json:"samples"`
+	GenerationLags map[string]GenerationLag   `json:"generationLags,omitempty"`
+}
+
+// DefaultRestartHistoryPath returns the per-cluster history file in the
+// user's cache directory. key identifies the cluster, e.g. its API server URL.
+func DefaultRestartHistoryPath(key string) (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(cacheDir, "kubectl-pulse", "restarts-"+hex.EncodeToString(sum[:8])+".json"), nil
+}
+
+// NewRestartHistory returns an empty history that will be written to path.
+func NewRestartHistory(path string) *RestartHistory {
+	return &RestartHistory{
+		path:    path,
+		Samples: make(map[string][]RestartSample),
+	}
+}
+
+// LoadRestartHistory reads the history at path. A missing file yields an
+// empty history that will be created on Save.
+func LoadRestartHistory(path string) (*RestartHistory, error) {
+	history := NewRestartHistory(path)
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return history, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, history); err != nil {
+		return nil, err
+	}
+	if history.Samples == nil {
+		history.Samples = make(map[string][]RestartSample)
+	}
+
+	return history, nil
+}
+
+// Baseline returns the sample to diff against for a window ending at now.
+// That is the newest sample taken at or before the window start when it
+// gives the count at the window start: it is within restartBaselineSlack of
+// it, or the next sample has the same count so the count did not change in
+// between. covered reports such a sample. Failing that it is the oldest
+// sample inside the window, which covers only part of it. ok is false when
+// there is neither, as when the only samples are long before the window.
+func (h *RestartHistory) Baseline(uid string, window time.Duration, now time.Time) (sample RestartSample, covered bool, ok bool) {
+	if h == nil {
+		return RestartSample{}, false, false
+	}
+
+	samples := h.Samples[uid]
+	if len(samples) == 0 {
+		return RestartSample{}, false, false
+	}
+
+	windowStart := now.Add(-window)
+	next := len(samples)
+	for next > 0 && samples[next-1].Time.After(windowStart) {
+		next--
+	}
+	if next > 0 {
+		before := samples[next-1]
+		if windowStart.Sub(before.Time) <= restartBaselineSlack(window) ||
+			(next < len(samples) && samples[next].Restarts == before.Restarts) {
+			return before, true, true
+		}
+	}
+	if next < len(samples) {
+		return samples[next], false, true
+	}
+
+	return RestartSample{}, false, false
+}
+
+// Record appends the current restart count of every pod and prunes samples
+// that have aged out of the retention period.
+func (h *RestartHistory) Record(pods []PodStatus, now time.Time) {
+	if h == nil {
+		return
+	}
+
+	for _, pod := range pods {
+		if pod.UID == "" {
+			continue
+		}
+		samples := h.Samples[pod.UID]
+		sample := RestartSample{Time: now, Restarts: pod.Restarts}
+		switch n := len(samples); {
+		case n > 1 && samples[n-2].Restarts == pod.Restarts && samples[n-1].Restarts == pod.Restarts:
+			// The count has not changed since the sample before the latest,
+			// so the latest only marks how long it has held
+			samples[n-1] = sample
+		case n > 1 && samples[n-1].Time.Sub(samples[n-2].Time) < restartHistorySpacing:
+			// The latest sample keeps being updated until it is a full
+			// spacing past the one before it
+			samples[n-1] = sample
+		default:
+			samples = append(samples, sample)
+		}
+		samples = thinRestartSamples(samples, now)
+		if len(samples) > restartHistoryMaxSamples {
+			samples = samples[len(samples)-restartHistoryMaxSamples:]
+		}
+		h.Samples[pod.UID] = samples
+	}
+
+	cutoff := now.Add(-restartHistoryRetention)
+	for uid, samples := range h.Samples {
+		// Keep one sample older than the cutoff so the longest window still
+		// has a baseline
+		first := 0
+		for first < len(samples)-1 && samples[first+1].Time.Before(cutoff) {
+			first++
+		}
+		samples = samples[first:]
+
+		if samples[len(samples)-1].Time.Before(cutoff) {
+			delete(h.Samples, uid)
+			continue
+		}
+		h.Samples[uid] = samples
+	}
+}
+
+// thinRestartSamples keeps one sample per interval of time, the oldest,
+// with intervals that double in width as samples age, staying under a sixth
+// of their age. Baseline needs a sample within half a window of the window
+// start, which this leaves for every window, while a pod restarting all day
+// keeps a few dozen samples rather than one per run. Intervals are aligned to
+// absolute time, so samples kept on one run are kept on later ones too.
+func thinRestartSamples(samples []RestartSample, now time.Time) []RestartSample {
+	var thinned []RestartSample
+	for i, sample := range samples {
+		width := restartHistorySpacing
+		for width*2 <= now.Sub(sample.Time)/6 {
+			width *= 2
+		}
+		if n := len(thinned); n > 0 && i < len(samples)-1 && thinned[n-1].Time.Truncate(width).Equal(sample.Time.Truncate(width)) {
+			continue
+		}
+		thinned = append(thinned, sample)
+	}
+	return thinned
+}
+
+// TrackGenerationLags records when each rollout whose controller has yet to
+// observe its generation was first seen so, and returns the lags by
+// namespace/name. Lags of rollouts that caught up are forgotten, as are ones
+// older than the retention period, e.g. of deleted Deployments. Rollouts
+// outside the ones given, as in other namespaces, are left alone.
+func (h *RestartHistory) TrackGenerationLags(rollouts []RolloutStatus, now time.Time) map[string]GenerationLag {
+	if h == nil {
+		return nil
+	}
+	if h.GenerationLags == nil {
+		h.GenerationLags = make(map[string]GenerationLag)
+	}
+
+	for _, rollout := range rollouts {
+		key := rollout.Namespace + "/" + rollout.Name
+		if rollout.ObservedGeneration >= rollout.Generation {
+			delete(h.GenerationLags, key)
+			continue
+		}
+		if lag, ok := h.GenerationLags[key]; !ok || lag.Generation != rollout.Generation {
+			h.GenerationLags[key] = GenerationLag{Generation: rollout.Generation, Since: now}
+		}
+	}
+
+	cutoff := now.Add(-restartHistoryRetention)
+	for key, lag := range h.GenerationLags {
+		if lag.Since.Before(cutoff) {
+			delete(h.GenerationLags, key)
+		}
+	}
+
+	return h.GenerationLags
+}
+
+// Checkpoint saves the history unless this process already saved it within
+// the sample spacing. Refreshes closer together than that, as in watch and
+// serve mode, mostly update the latest samples, so writing the whole history
+// out on each of them is not worth it; a new process always saves.
+func (h *RestartHistory) Checkpoint(now time.Time) error {
+	if h == nil || now.Sub(h.saved) < restartHistorySpacing {
+		return nil
+	}
+	if err := h.Save(); err != nil {
+		return err
+	}
+	h.saved = now
+	return nil
+}
+
+func (h *RestartHistory) Save() error {
+	if h == nil || h.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(h)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o755); err != nil {
+		return err
+	}
+
+	// Write then rename so concurrent invocations never see a partial file
+	tmp, err := os.CreateTemp(filepath.Dir(h.path), filepath.Base(h.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), h.path)
+}
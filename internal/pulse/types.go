@@ -2,21 +2,133 @@ package pulse
 
 import "time"
 
+type HealthStatus string
+
+const (
+	StatusHealthy  HealthStatus = "HEALTHY"
+	StatusWarning  HealthStatus = "WARNING"
+	StatusCritical HealthStatus = "CRITICAL"
+)
+
+// severity orders statuses so the worst of several can be picked.
+func (s HealthStatus) severity() int {
+	switch s {
+	case StatusHealthy:
+		return 0
+	case StatusWarning:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// ExitCodeError is the exit code for a pulse that could not be taken, the
+// Nagios UNKNOWN state.
+const ExitCodeError = 3
+
+// ExitCode maps the status to a Nagios-style plugin exit code: 0 for HEALTHY,
+// 1 for WARNING and 2 for CRITICAL.
+func (s HealthStatus) ExitCode() int {
+	return s.severity()
+}
+
+// ContainerReason is a waiting or terminated reason reported for one of a
+// pod's containers, e.g. CrashLoopBackOff or OOMKilled.
+type ContainerReason struct {
+	Container string `json:"container"`
+	Reason    string `json:"reason"`
+	// FinishedAt is set for termination reasons and zero for waiting ones
+	FinishedAt time.Time `json:"finishedAt,omitzero"`
+}
+
+// ContainerStatus attributes restarts to a single container of a pod. The
+// termination fields describe the container's last termination, if any.
+type ContainerStatus struct {
+	Name            string    `json:"name"`
+	Image           string    `json:"image"`
+	Init            bool      `json:"init,omitempty"`
+	Ephemeral       bool      `json:"ephemeral,omitempty"`
+	Restarts        int32     `json:"restarts"`
+	LastTermination time.Time `json:"lastTermination,omitzero"`
+	ExitCode        int32     `json:"exitCode,omitempty"`
+	Reason          string    `json:"reason,omitempty"`
+}
+
+// OOMKill describes a container whose most recent termination was OOMKilled.
+type OOMKill struct {
+	Namespace     string `json:"namespace"`
+	Pod           string `json:"pod"`
+	Container     string `json:"container"`
+	MemoryRequest string `json:"memoryRequest,omitempty"`
+	MemoryLimit   string `json:"memoryLimit,omitempty"`
+	// Kills estimates how often the container was OOM-killed within the time
+	// window from the pod's restarts within it. The API only retains the last
+	// termination, so restarts in between are assumed to be OOM kills too.
+	Kills int32 `json:"kills"`
+	// Restarts is the container's total restart count, for context
+	Restarts int32     `json:"restarts"`
+	KilledAt time.Time `json:"killedAt"`
+}
+
+// Causes a pod can be pending for, see PendingPod.
+const (
+	PendingAwaitingScheduler = "AwaitingScheduler"
+	PendingSchedulingGated   = "SchedulingGated"
+	PendingUnschedulable     = "Unschedulable"
+	PendingVolumeBinding     = "VolumeBinding"
+	PendingImagePull         = "ImagePull"
+	PendingInitContainers    = "InitContainers"
+	PendingContainerCreating = "ContainerCreating"
+)
+
+// PendingPod explains why a pod has not started running. Message carries the
+// scheduler's or kubelet's explanation where there is one.
+type PendingPod struct {
+	Namespace string    `json:"namespace"`
+	Pod       string    `json:"pod"`
+	Cause     string    `json:"cause"`
+	Message   string    `json:"message,omitempty"`
+	Since     time.Time `json:"since"`
+}
+
 type PodStatus struct {
-	Name        string
-	Namespace   string
-	Status      string
-	Restarts    int32
-	LastRestart time.Time
+	UID       string    `json:"-"`
+	Name      string    `json:"name"`
+	Namespace string    `json:"namespace"`
+	Status    string    `json:"status"`
+	Created   time.Time `json:"created,omitzero"`
+	Restarts  int32     `json:"restarts"`
+	// RecentRestarts is the number of restarts within the analyzed time
+	// window, measured against the restart history when one is available
+	RecentRestarts int32             `json:"recentRestarts"`
+	LastRestart    time.Time         `json:"lastRestart,omitzero"`
+	Containers     []ContainerStatus `json:"containers,omitempty"`
+	Reasons        []ContainerReason `json:"reasons,omitempty"`
+	OOMKills       []OOMKill         `json:"-"`
+	// Pending is set for pods in the Pending phase
+	Pending *PendingPod `json:"-"`
+	// Labels are matched against service selectors
+	Labels map[string]string `json:"-"`
+	// Severity grades the pod's total restarts; it is only set on top
+	// offenders
+	Severity HealthStatus `json:"severity,omitempty"`
 }
 
 type PodStatusDistribution struct {
-	Running   int
-	Pending   int
-	Failed    int
-	Succeeded int
-	Unknown   int
-	Total     int
+	Running   int `json:"running"`
+	Pending   int `json:"pending"`
+	Failed    int `json:"failed"`
+	Succeeded int `json:"succeeded"`
+	Unknown   int `json:"unknown"`
+	Total     int `json:"total"`
+}
+
+// NamespacePods holds the per-namespace pod counts graded by the health policy.
+type NamespacePods struct {
+	Pending int
+	Failed  int
+	// ContainerReasons is the number of containers reporting a problem reason
+	ContainerReasons int
 }
 
 func (p *PodStatusDistribution) GetPercentage(status string) float64 {
@@ -40,10 +152,431 @@ func (p *PodStatusDistribution) GetPercentage(status string) float64 {
 	}
 }
 
+type NodeStatus struct {
+	Name string
+	// Ready is the status of the Ready condition: "True", "False" or "Unknown"
+	Ready         string
+	Unschedulable bool
+	// Pressure lists the pressure conditions (MemoryPressure, DiskPressure,
+	// PIDPressure, NetworkUnavailable) currently reported as True
+	Pressure []string
+}
+
+type NodeHealth struct {
+	Ready              int      `json:"ready"`
+	NotReady           int      `json:"notReady"`
+	Unknown            int      `json:"unknown"`
+	SchedulingDisabled int      `json:"schedulingDisabled"`
+	Total              int      `json:"total"`
+	NotReadyNodes      []string `json:"notReadyNodes"`
+	CordonedNodes      []string `json:"cordonedNodes"`
+	MemoryPressure     []string `json:"memoryPressure"`
+	DiskPressure       []string `json:"diskPressure"`
+	PIDPressure        []string `json:"pidPressure"`
+	NetworkUnavailable []string `json:"networkUnavailable"`
+}
+
+type WorkloadStatus struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Desired   int32  `json:"desired"`
+	Ready     int32  `json:"ready"`
+	Available int32  `json:"available"`
+}
+
+// RolloutStatus captures a Deployment's rollout progress together with the
+// ReplicaSet of its current revision.
+type RolloutStatus struct {
+	Name               string
+	Namespace          string
+	Generation         int64
+	ObservedGeneration int64
+	// ProgressReason and ProgressMessage come from the Progressing condition
+	ProgressReason      string
+	ProgressMessage     string
+	Revision            string
+	ReplicaSet          string
+	ReplicaSetCreated   time.Time
+	ReplicaSetDesired   int32
+	ReplicaSetAvailable int32
+}
+
+type StuckRollout struct {
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace"`
+	Revision   string `json:"revision"`
+	ReplicaSet string `json:"replicaSet"`
+	Reason     string `json:"reason"`
+	Message    string `json:"message"`
+}
+
+// Event is a core/v1 or events.k8s.io/v1 Event reduced to what pulse reads.
+// Namespace, Kind and Name identify the object the event is about.
+type Event struct {
+	Type      string
+	Reason    string
+	Message   string
+	Namespace string
+	Kind      string
+	Name      string
+	// Count includes the occurrences deduplicated into the event, either by
+	// the legacy count field or an event series
+	Count    int32
+	LastSeen time.Time
+}
+
+// EventReason summarizes the events sharing a reason within the time window.
+type EventReason struct {
+	Reason string `json:"reason"`
+	Count  int    `json:"count"`
+	// Objects are the affected objects, most occurrences first
+	Objects []EventObject `json:"objects"`
+}
+
+type EventObject struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Count     int    `json:"count"`
+	// Message is the message of the most recent occurrence
+	Message  string    `json:"message"`
+	LastSeen time.Time `json:"lastSeen"`
+}
+
+// VolumeClaimStatus is a PersistentVolumeClaim reduced to what the storage
+// analysis reads.
+type VolumeClaimStatus struct {
+	Namespace string
+	Name      string
+	Phase     string
+	// WaitingForConsumer is set while a WaitForFirstConsumer claim has no
+	// pod scheduled to use it, which is why it is Pending
+	WaitingForConsumer bool
+	Created            time.Time
+}
+
+// VolumeStatus is a PersistentVolume reduced to what the storage analysis
+// reads. ClaimNamespace and Claim name the claim it is or was bound to.
+type VolumeStatus struct {
+	Name           string
+	Phase          string
+	Message        string
+	ClaimNamespace string
+	Claim          string
+	ReclaimPolicy  string
+}
+
+// VolumeAttachmentStatus is a VolumeAttachment reduced to its errors.
+type VolumeAttachmentStatus struct {
+	Name        string
+	Node        string
+	Volume      string
+	AttachError string
+	DetachError string
+}
+
+// StorageStatus holds the storage objects the storage analysis reads.
+// Volumes and Attachments are cluster-scoped and left empty when the caller
+// may not list them.
+type StorageStatus struct {
+	Claims      []VolumeClaimStatus
+	Volumes     []VolumeStatus
+	Attachments []VolumeAttachmentStatus
+}
+
+// Statuses of StorageIssue besides the claim and volume phases.
+const (
+	StorageAttachError = "AttachError"
+	StorageDetachError = "DetachError"
+)
+
+// StorageIssue is a PersistentVolumeClaim, PersistentVolume or
+// VolumeAttachment in a problem state, or a pod whose volumes fail to attach
+// or mount.
+type StorageIssue struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// Status is the claim or volume phase (Pending, Lost, Failed, Released),
+	// AttachError or DetachError for attachments, and the event reason
+	// (FailedAttachVolume, FailedMount) for pods
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+	// Since is when a Pending claim was created
+	Since time.Time `json:"since,omitzero"`
+}
+
+// ServicePort is a port a Service exposes, by number and optional name.
+type ServicePort struct {
+	Name string
+	Port int32
+}
+
+// ServiceStatus counts the endpoints behind a Service. Endpoints excludes
+// terminating endpoints, and Pods is the number of running or pending pods
+// matching Selector.
+type ServiceStatus struct {
+	Namespace      string            `json:"namespace"`
+	Name           string            `json:"name"`
+	Type           string            `json:"-"`
+	Selector       map[string]string `json:"selector"`
+	Ports          []ServicePort     `json:"-"`
+	Pods           int               `json:"pods"`
+	ReadyEndpoints int               `json:"readyEndpoints"`
+	Endpoints      int               `json:"endpoints"`
+}
+
+// RouteBackend is a Service an Ingress or HTTPRoute sends traffic to, by port
+// number or, for Ingresses, by port name.
+type RouteBackend struct {
+	Namespace string
+	Service   string
+	Port      int32
+	PortName  string
+}
+
+// RouteCondition is a condition a Gateway reported on an HTTPRoute it is a
+// parent of.
+type RouteCondition struct {
+	Parent  string
+	Type    string
+	Status  string
+	Reason  string
+	Message string
+}
+
+// RouteStatus is an Ingress or HTTPRoute reduced to its backends and, for
+// HTTPRoutes, the conditions reported by its Gateways.
+type RouteStatus struct {
+	Kind       string
+	Namespace  string
+	Name       string
+	Backends   []RouteBackend
+	Conditions []RouteCondition
+}
+
+// Reasons of a RouteIssue.
+const (
+	RouteServiceNotFound  = "ServiceNotFound"
+	RoutePortNotFound     = "PortNotFound"
+	RouteNoReadyEndpoints = "NoReadyEndpoints"
+	RouteNotAccepted      = "NotAccepted"
+	RouteRefsNotResolved  = "RefsNotResolved"
+)
+
+// RouteIssue is a problem with an Ingress or HTTPRoute: a backend that does
+// not exist, does not expose the port, or has no ready endpoints, or a route
+// a Gateway did not accept or could not resolve the references of.
+type RouteIssue struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Reason    string `json:"reason"`
+	// Backend is set for backend issues as service:port, prefixed with the
+	// service's namespace when it is not the route's
+	Backend string `json:"backend,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// CertificateStatus is a certificate read from a kubernetes.io/tls Secret or
+// a cert-manager Certificate.
+type CertificateStatus struct {
+	// Kind is Secret or Certificate
+	Kind      string
+	Namespace string
+	Name      string
+	// SecretName is the Secret a Certificate stores its certificate in
+	SecretName string
+	// Subject is the certificate's common name, or its first DNS name
+	Subject  string
+	NotAfter time.Time
+	// Ready, Reason and Message come from a Certificate's Ready condition
+	Ready   string
+	Reason  string
+	Message string
+	// Error is set when a Secret's certificate cannot be parsed
+	Error string
+}
+
+// CertificateInventory holds the certificates the certificate analysis
+// reads. SecretsForbidden is set when the caller may not list Secrets, so
+// only cert-manager Certificates were read.
+type CertificateInventory struct {
+	Certificates     []CertificateStatus
+	SecretsForbidden bool
+}
+
+// Statuses of a CertificateIssue.
+const (
+	CertificateExpired  = "Expired"
+	CertificateExpiring = "Expiring"
+	CertificateNotReady = "NotReady"
+	CertificateInvalid  = "Invalid"
+)
+
+type CertificateIssue struct {
+	Kind      string    `json:"kind"`
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Subject   string    `json:"subject,omitempty"`
+	Status    string    `json:"status"`
+	NotAfter  time.Time `json:"notAfter,omitzero"`
+	Message   string    `json:"message,omitempty"`
+}
+
+type CertificateHealth struct {
+	// Checked is the number of certificates read
+	Checked int `json:"checked"`
+	// Horizon is how far ahead certificates are reported as expiring
+	Horizon Duration `json:"horizon"`
+	// SecretsForbidden is set when only cert-manager Certificates could be
+	// read
+	SecretsForbidden bool               `json:"secretsForbidden,omitempty"`
+	Issues           []CertificateIssue `json:"issues"`
+}
+
+// AutoscalerMetric compares the current value of a metric an autoscaler
+// scales on to its target, e.g. cpu at 92% for a 70% target.
+type AutoscalerMetric struct {
+	Name    string `json:"name"`
+	Current string `json:"current"`
+	Target  string `json:"target"`
+}
+
+// Problems an AutoscalerStatus is reported with.
+const (
+	AutoscalerAtMaxReplicas   = "AtMaxReplicas"
+	AutoscalerScalingInactive = "ScalingInactive"
+	AutoscalerScalingLimited  = "ScalingLimited"
+)
+
+// AutoscalerStatus is a HorizontalPodAutoscaler with its replica bounds and
+// metrics. The condition fields come from its ScalingActive and
+// ScalingLimited conditions.
+type AutoscalerStatus struct {
+	Namespace       string             `json:"namespace"`
+	Name            string             `json:"name"`
+	Target          string             `json:"target"`
+	MinReplicas     int32              `json:"minReplicas"`
+	MaxReplicas     int32              `json:"maxReplicas"`
+	CurrentReplicas int32              `json:"currentReplicas"`
+	DesiredReplicas int32              `json:"desiredReplicas"`
+	Metrics         []AutoscalerMetric `json:"metrics"`
+
+	ScalingActive         string `json:"-"`
+	ScalingActiveReason   string `json:"-"`
+	ScalingActiveMessage  string `json:"-"`
+	ScalingLimited        string `json:"-"`
+	ScalingLimitedReason  string `json:"-"`
+	ScalingLimitedMessage string `json:"-"`
+
+	// Problems and Message are set by the analyzer: the problems found, and
+	// the reason and message of the condition that best explains them
+	Problems []string `json:"problems"`
+	Message  string   `json:"message,omitempty"`
+}
+
+// Sections of ClusterHealth that can be reported in ClusterHealth.Incomplete.
+const (
+	SectionNodes        = "nodes"
+	SectionWorkloads    = "workloads"
+	SectionRollouts     = "rollouts"
+	SectionEvents       = "events"
+	SectionStorage      = "storage"
+	SectionServices     = "services"
+	SectionRoutes       = "routes"
+	SectionCertificates = "certificates"
+	SectionAutoscalers  = "autoscalers"
+)
+
+const (
+	ChangePodCreated   = "PodCreated"
+	ChangePodDeleted   = "PodDeleted"
+	ChangePhaseChanged = "PhaseChanged"
+	ChangeRestarted    = "Restarted"
+)
+
+// PodChange is a difference between two successive pulses in watch mode.
+type PodChange struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	Change    string `json:"change"`
+	Detail    string `json:"detail,omitempty"`
+}
+
+// Signal is one count graded by the health policy, totalled across the
+// cluster. Warning and Critical are the cluster-wide bounds; Status is the
+// worst grade across namespaces, which may have their own bounds.
+type Signal struct {
+	Name     string
+	Value    int
+	Warning  *int
+	Critical *int
+	Status   HealthStatus
+}
+
 type ClusterHealth struct {
-	RecentRestarts        int
-	RecentRestartPods     []PodStatus
-	TopOffenders          []PodStatus
-	PodStatusDistribution PodStatusDistribution
-	TimeWindow            int
+	Status HealthStatus `json:"status"`
+	// RecentRestarts is the number of container restarts within the time
+	// window across all pods in RecentRestartPods
+	RecentRestarts    int         `json:"recentRestarts"`
+	RecentRestartPods []PodStatus `json:"recentRestartPods"`
+	// RestartBaseline is the oldest restart history sample the restart
+	// counts were measured against; zero when no history was available
+	RestartBaseline       time.Time             `json:"restartBaseline,omitzero"`
+	TopOffenders          []PodStatus           `json:"topOffenders"`
+	PodStatusDistribution PodStatusDistribution `json:"podStatusDistribution"`
+	// ContainerReasons counts containers by waiting or terminated reason
+	ContainerReasons map[string]int `json:"containerReasons"`
+	// NamespacePods breaks pod counts down per namespace for the health policy
+	NamespacePods map[string]NamespacePods `json:"-"`
+	// Signals are the graded counts the status was derived from
+	Signals  []Signal  `json:"-"`
+	OOMKills []OOMKill `json:"oomKills"`
+	// Nodes is nil when the caller is not allowed to list nodes
+	Nodes             *NodeHealth      `json:"nodes"`
+	DegradedWorkloads []WorkloadStatus `json:"degradedWorkloads"`
+	StuckRollouts     []StuckRollout   `json:"stuckRollouts"`
+	// Autoscalers are the HPAs pinned at maxReplicas, unable to compute a
+	// scale or limited in scaling
+	Autoscalers []AutoscalerStatus `json:"autoscalers"`
+	// UnhealthyServices are the services with no ready endpoints or with
+	// most of their endpoints not ready
+	UnhealthyServices []ServiceStatus `json:"unhealthyServices"`
+	// RouteIssues lists the Ingresses and HTTPRoutes with broken backends or
+	// rejected by their Gateway
+	RouteIssues []RouteIssue `json:"routeIssues"`
+	// PendingPods explains every Pending pod, longest pending first
+	PendingPods []PendingPod `json:"pendingPods"`
+	// WarningEvents groups the Warning events within the time window by
+	// reason, most occurrences first
+	WarningEvents []EventReason `json:"warningEvents"`
+	// Certificates is nil when neither Secrets nor cert-manager
+	// Certificates can be read
+	Certificates *CertificateHealth `json:"certificates"`
+	// StorageIssues lists claims, volumes, attachments and pods with
+	// storage problems
+	StorageIssues []StorageIssue `json:"storageIssues"`
+	TimeWindow    int            `json:"timeWindowMinutes"`
+	// Changes lists what changed since the previous frame in watch mode
+	Changes []PodChange `json:"changes,omitempty"`
+	// Incomplete names the sections left out because they timed out
+	Incomplete []string `json:"incomplete,omitempty"`
+	// Forbidden names the sections left out because the caller may not
+	// read them
+	Forbidden []string `json:"forbidden,omitempty"`
+}
+
+// ClusterResult is the outcome of a pulse against a single kubeconfig context.
+// Exactly one of Health and Error is set.
+type ClusterResult struct {
+	Context string         `json:"context"`
+	Health  *ClusterHealth `json:"health,omitempty"`
+	Error   string         `json:"error,omitempty"`
+}
+
+type FleetHealth struct {
+	Status   HealthStatus    `json:"status"`
+	Clusters []ClusterResult `json:"clusters"`
 }
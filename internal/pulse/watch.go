@@ -0,0 +1,34 @@
+package pulse
+
+import "context"
+
+// Watcher renders successive pulses of the same cluster and annotates each
+// frame with what changed since the previous one.
+type Watcher struct {
+	service  *Service
+	previous []PodStatus
+	primed   bool
+}
+
+func NewWatcher(service *Service) *Watcher {
+	return &Watcher{
+		service: service,
+	}
+}
+
+// Next takes a fresh pulse and renders it with the service's formatter. The
+// first frame has no changes since there is nothing to compare it to.
+func (w *Watcher) Next(ctx context.Context, timeWindowMinutes int, podAmount int, namespace string) (string, error) {
+	health, pods, err := w.service.collectClusterHealth(ctx, timeWindowMinutes, podAmount, namespace)
+	if err != nil {
+		return "", err
+	}
+
+	if w.primed {
+		health.Changes = w.service.analyzer.DiffPods(w.previous, pods, namespace)
+	}
+	w.previous = pods
+	w.primed = true
+
+	return w.service.formatter.FormatClusterHealth(health)
+}